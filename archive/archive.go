@@ -0,0 +1,158 @@
+// Package archive packs a directory tree into a deterministic ZIP archive
+// (so splitting the same directory twice yields the same plaintext bytes,
+// and therefore the same SHA-256) and unpacks one back out, guarding
+// against path-traversal entries.
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Build walks root and packs every regular file under it into a ZIP archive
+// in memory, with entries sorted by path so the output is deterministic.
+// Symlinks are rejected rather than followed or stored, since a share
+// archive should not be able to smuggle a link outside itself.
+func Build(root string) ([]byte, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("archive: %s is a symlink, which -in-dir does not support", path)
+		}
+		if d.IsDir() {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("archive: failed to walk %s: %w", root, err)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, p := range paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil, fmt.Errorf("archive: failed to compute relative path for %s: %w", p, err)
+		}
+		if err := addFile(zw, p, filepath.ToSlash(rel)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("archive: failed to finalize zip: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func addFile(zw *zip.Writer, srcPath, name string) error {
+	f, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("archive: failed to open %s: %w", srcPath, err)
+	}
+	defer f.Close()
+
+	hdr := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	// A fixed, zero-value mod time keeps the archive byte-identical across
+	// runs over an unchanged directory.
+	w, err := zw.CreateHeader(hdr)
+	if err != nil {
+		return fmt.Errorf("archive: failed to add %s: %w", name, err)
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("archive: failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Extract unpacks a ZIP archive produced by Build into destDir, rejecting
+// any entry with an absolute path and any symlink entry; ".." components
+// in an entry's name are clamped (see safeJoin), so no entry lands outside
+// destDir.
+func Extract(data []byte, destDir string) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("archive: not a valid zip archive: %w", err)
+	}
+
+	destDir, err = filepath.Abs(destDir)
+	if err != nil {
+		return fmt.Errorf("archive: failed to resolve %s: %w", destDir, err)
+	}
+
+	for _, f := range zr.File {
+		target, err := safeJoin(destDir, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("archive: refusing to extract symlink entry %q", f.Name)
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0o700); err != nil {
+				return fmt.Errorf("archive: failed to create directory %s: %w", target, err)
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+			return fmt.Errorf("archive: failed to create parent directory for %s: %w", target, err)
+		}
+		if err := extractFile(f, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("archive: failed to open entry %q: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("archive: failed to create %s: %w", target, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, rc); err != nil {
+		return fmt.Errorf("archive: failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+// safeJoin resolves name against destDir the way filepath-securejoin does:
+// it rejects absolute paths and clamps any ".." component that would climb
+// above destDir to destDir itself (so "../../etc/passwd" becomes
+// destDir/etc/passwd), without ever touching the filesystem for symlink resolution
+// (Extract refuses symlink entries outright, so there is nothing to race).
+func safeJoin(destDir, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("archive: entry %q has an absolute path", name)
+	}
+	cleaned := filepath.Clean("/" + filepath.ToSlash(name))[1:]
+	if cleaned == "" || strings.HasPrefix(cleaned, "..") {
+		return "", fmt.Errorf("archive: entry %q escapes the destination directory", name)
+	}
+	target := filepath.Join(destDir, cleaned)
+	if target != destDir && !strings.HasPrefix(target, destDir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive: entry %q escapes the destination directory", name)
+	}
+	return target, nil
+}
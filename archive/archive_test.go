@@ -0,0 +1,82 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildExtractRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	files := map[string]string{
+		"top.txt":      "hello",
+		"sub/deep.txt": "world",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile %s: %v", name, err)
+		}
+	}
+
+	data, err := Build(src)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := Extract(data, dst); err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	for name, content := range files {
+		got, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil {
+			t.Fatalf("reading extracted %s: %v", name, err)
+		}
+		if string(got) != content {
+			t.Fatalf("extracted %s = %q, want %q", name, got, content)
+		}
+	}
+}
+
+func TestBuildIsDeterministic(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "b.txt"), []byte("b"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	first, err := Build(src)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	second, err := Build(src)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Fatalf("Build of the same directory produced different bytes across runs")
+	}
+}
+
+func TestSafeJoinClampsTraversal(t *testing.T) {
+	dest := t.TempDir()
+	target, err := safeJoin(dest, "../../etc/passwd")
+	if err != nil {
+		t.Fatalf("safeJoin: %v", err)
+	}
+	if !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+		t.Fatalf("safeJoin(%q) = %q, escapes destDir %q", "../../etc/passwd", target, dest)
+	}
+}
+
+func TestSafeJoinRejectsAbsolutePath(t *testing.T) {
+	if _, err := safeJoin(t.TempDir(), "/etc/passwd"); err == nil {
+		t.Fatalf("safeJoin should reject an absolute path entry")
+	}
+}
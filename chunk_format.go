@@ -0,0 +1,256 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	shamir "sss_cli/shamir"
+	"sss_cli/stream"
+)
+
+// -------------------- chunked/streaming split --------------------
+//
+// `split -in <file> -chunk <size>` avoids the current os.ReadFile + [][]byte
+// approach (O(n*secret_size) memory) for secrets too large to comfortably
+// hold in memory: it reads the input one chunk at a time, runs Shamir on
+// each chunk independently, and writes one share stream file per share
+// (share_01.sss, ..., share_NN.sss). `combine -streams` reads any t of
+// those N files back, chunk by chunk, verifying the reassembled secret's
+// SHA-256 against the streams' manifest.
+
+var chunkSizeRe = regexp.MustCompile(`(?i)^(\d+)\s*(b|kib|mib|gib)?$`)
+
+// parseChunkSize parses sizes like "4MiB", "1024", or "64KiB" into bytes.
+func parseChunkSize(s string) (int, error) {
+	m := chunkSizeRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid -chunk size %q: expected e.g. 4MiB, 64KiB, or a plain byte count", s)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid -chunk size %q: %w", s, err)
+	}
+	switch strings.ToLower(m[2]) {
+	case "", "b":
+		return n, nil
+	case "kib":
+		return n * 1024, nil
+	case "mib":
+		return n * 1024 * 1024, nil
+	case "gib":
+		return n * 1024 * 1024 * 1024, nil
+	default:
+		return 0, fmt.Errorf("invalid -chunk size unit in %q", s)
+	}
+}
+
+// cmdSplitChunked implements 'split -in <file> -chunk <size>'. It requires
+// -outdir, since N share stream files must land somewhere.
+func cmdSplitChunked(inFile, outDir string, n, t, chunkSize int) error {
+	if outDir == "" {
+		return fmt.Errorf("-chunk requires -outdir to write share streams to")
+	}
+	if chunkSize <= 0 {
+		return fmt.Errorf("-chunk size must be positive")
+	}
+
+	info, err := os.Stat(inFile)
+	if err != nil {
+		return fmt.Errorf("failed to stat -in: %w", err)
+	}
+	originalLen := info.Size()
+	totalChunks := int((originalLen + int64(chunkSize) - 1) / int64(chunkSize))
+	if totalChunks == 0 {
+		totalChunks = 1 // an empty file still produces one (empty) chunk
+	}
+
+	plainHash, err := hashFile(inFile)
+	if err != nil {
+		return fmt.Errorf("failed to hash -in: %w", err)
+	}
+
+	if err := os.MkdirAll(outDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create outdir: %w", err)
+	}
+
+	files := make([]*os.File, n)
+	defer func() {
+		for _, f := range files {
+			if f != nil {
+				f.Close()
+			}
+		}
+	}()
+	for i := 0; i < n; i++ {
+		name := filepath.Join(outDir, fmt.Sprintf("share_%02d.sss", i+1))
+		f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", name, err)
+		}
+		files[i] = f
+		if err := stream.WriteManifest(f, stream.Manifest{
+			N: n, T: t, Index: i + 1,
+			ChunkSize: chunkSize, TotalChunks: totalChunks,
+			OriginalLen: originalLen, PlainHash: plainHash,
+		}); err != nil {
+			return fmt.Errorf("failed to write manifest to %s: %w", name, err)
+		}
+	}
+
+	in, err := os.Open(inFile)
+	if err != nil {
+		return fmt.Errorf("failed to reopen -in: %w", err)
+	}
+	defer in.Close()
+
+	buf := make([]byte, chunkSize)
+	for chunkIdx := 0; chunkIdx < totalChunks; chunkIdx++ {
+		read, err := io.ReadFull(in, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return fmt.Errorf("failed reading chunk %d: %w", chunkIdx, err)
+		}
+		chunk := buf[:read]
+
+		shares, err := shamir.Split(mustNonEmpty(chunk), n, t)
+		if err != nil {
+			return fmt.Errorf("failed to split chunk %d: %w", chunkIdx, err)
+		}
+		for i, f := range files {
+			if err := stream.WriteRecord(f, uint32(chunkIdx), shares[i]); err != nil {
+				return fmt.Errorf("failed to write chunk %d to share %d: %w", chunkIdx, i+1, err)
+			}
+		}
+		zero2D(shares)
+	}
+
+	for i, f := range files {
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to close share stream %d: %w", i+1, err)
+		}
+		files[i] = nil
+	}
+
+	fmt.Printf("Split OK (chunked). n=%d t=%d; %d chunks of up to %d bytes; wrote %d share streams to %s\n",
+		n, t, totalChunks, chunkSize, n, outDir)
+	return nil
+}
+
+// mustNonEmpty substitutes a single zero byte for an empty chunk, since
+// shamir.Split requires a non-empty secret; cmdCombineChunked trims the
+// reconstructed output back to the manifest's OriginalLen regardless.
+func mustNonEmpty(b []byte) []byte {
+	if len(b) == 0 {
+		return []byte{0}
+	}
+	return b
+}
+
+func hashFile(path string) ([sha256.Size]byte, error) {
+	var out [sha256.Size]byte
+	f, err := os.Open(path)
+	if err != nil {
+		return out, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return out, err
+	}
+	copy(out[:], h.Sum(nil))
+	return out, nil
+}
+
+// cmdCombineChunked implements 'combine -streams f1.sss,f2.sss,...'. It
+// reads any t of the N share streams and reconstructs the original file
+// chunk-by-chunk, verifying the final SHA-256 against the manifest.
+func cmdCombineChunked(streamPaths []string, outFile string) error {
+	if outFile == "" {
+		return fmt.Errorf("-streams requires -out to write the reconstructed file to")
+	}
+
+	files := make([]*os.File, len(streamPaths))
+	defer func() {
+		for _, f := range files {
+			if f != nil {
+				f.Close()
+			}
+		}
+	}()
+
+	var manifest stream.Manifest
+	for i, p := range streamPaths {
+		f, err := os.Open(p)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", p, err)
+		}
+		files[i] = f
+		m, err := stream.ReadManifest(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if i == 0 {
+			manifest = m
+		} else if m.TotalChunks != manifest.TotalChunks || m.ChunkSize != manifest.ChunkSize ||
+			m.OriginalLen != manifest.OriginalLen || m.PlainHash != manifest.PlainHash {
+			return fmt.Errorf("%s: manifest does not match %s (different split?)", p, streamPaths[0])
+		}
+	}
+	if len(files) < manifest.T {
+		return fmt.Errorf("need at least %d share streams, got %d", manifest.T, len(files))
+	}
+
+	out, err := os.OpenFile(outFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create -out: %w", err)
+	}
+	defer out.Close()
+
+	h := sha256.New()
+	written := int64(0)
+	for chunkIdx := 0; chunkIdx < manifest.TotalChunks; chunkIdx++ {
+		parts := make([][]byte, len(files))
+		for i, f := range files {
+			idx, payload, err := stream.ReadRecord(f)
+			if err != nil {
+				return fmt.Errorf("share stream %d: failed to read chunk %d: %w", i+1, chunkIdx, err)
+			}
+			if int(idx) != chunkIdx {
+				return fmt.Errorf("share stream %d: expected chunk %d, got %d", i+1, chunkIdx, idx)
+			}
+			parts[i] = payload
+		}
+		chunk, err := shamir.Combine(parts)
+		if err != nil {
+			return fmt.Errorf("failed to combine chunk %d: %w", chunkIdx, err)
+		}
+
+		remaining := manifest.OriginalLen - written
+		if int64(len(chunk)) > remaining {
+			chunk = chunk[:remaining] // strip the single placeholder byte used for an empty final chunk
+		}
+		if _, err := h.Write(chunk); err != nil {
+			return fmt.Errorf("failed hashing chunk %d: %w", chunkIdx, err)
+		}
+		if _, err := out.Write(chunk); err != nil {
+			return fmt.Errorf("failed writing chunk %d: %w", chunkIdx, err)
+		}
+		written += int64(len(chunk))
+		zero(chunk)
+	}
+
+	var gotHash [sha256.Size]byte
+	copy(gotHash[:], h.Sum(nil))
+	if gotHash != manifest.PlainHash || written != manifest.OriginalLen {
+		return fmt.Errorf("reconstructed file failed its SHA-256 integrity check")
+	}
+
+	fmt.Printf("Recovered secret written to %s (%d bytes, %d chunks, integrity check: PASS)\n",
+		outFile, written, manifest.TotalChunks)
+	return nil
+}
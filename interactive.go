@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"runtime"
+	"strings"
+
+	"sss_cli/archive"
+	shamir "sss_cli/shamir"
+)
+
+// -------------------- interactive combine REPL --------------------
+//
+// `combine -interactive` matches the real ceremony use case: holders read
+// their share out loud (or hand over a file) one at a time, and you want
+// immediate feedback on typos rather than discovering a bad share only
+// after everyone has gone through the trouble of gathering. It also offers
+// a stronger self-test than split's: once enough shares are in, you can
+// combine different t-sized subsets of what was entered and confirm they
+// agree, rather than trusting a single combination.
+
+// cmdCombineInteractive reads one share per line from r (a Base64 string, or
+// a path to a file containing one), validating and reporting progress after
+// each to w, until the user asks to combine. It otherwise behaves like the
+// non-interactive combine path: -out/-out-dir, passphrase/RS/directory-
+// archive framing, and the SHA-256 archive integrity check all still apply.
+// r and w are parameterized (rather than hardcoding stdin/stdout) so this
+// command's dispatch and validation logic can be exercised by tests.
+func cmdCombineInteractive(r io.Reader, w io.Writer, threshold int, passphrase string, fix bool, outFile, outDirPath string) error {
+	scanner := bufio.NewScanner(r)
+	fmt.Fprintln(w, "Interactive combine. Enter a share (Base64, or a path to a file containing one).")
+	fmt.Fprintln(w, "Commands: 'combine' to reconstruct now, 'done' when finished, 'quit' to abort.")
+
+	var (
+		rawLen      = -1
+		isDir       bool
+		archiveSet  bool
+		archiveHash [sha256.Size]byte
+		archiveLen  int
+		shareOpts   *shamir.Options
+		plain       [][]byte
+	)
+	defer func() { zero2D(plain) }()
+	seenTags := map[byte]bool{}
+
+	for {
+		if threshold > 0 {
+			fmt.Fprintf(w, "[%d/%d shares] > ", len(plain), threshold)
+		} else {
+			fmt.Fprintf(w, "[%d shares] > ", len(plain))
+		}
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		switch strings.ToLower(line) {
+		case "quit", "abort", "exit":
+			return fmt.Errorf("aborted by user")
+		case "done", "combine":
+			if len(plain) < 2 {
+				fmt.Fprintln(w, "Need at least 2 shares to combine.")
+				continue
+			}
+			if threshold > 0 && len(plain) < threshold {
+				fmt.Fprintf(w, "Only %d of %d required shares entered so far.\n", len(plain), threshold)
+				continue
+			}
+			return finishInteractiveCombine(w, plain, shareOpts, threshold, isDir, archiveHash, archiveLen, outFile, outDirPath)
+		}
+
+		b64 := line
+		if data, err := os.ReadFile(line); err == nil {
+			b64 = strings.TrimSpace(string(data))
+		}
+		raw, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			fmt.Fprintf(w, "  rejected: not valid Base64 (%v)\n", err)
+			continue
+		}
+
+		if rawLen == -1 {
+			rawLen = len(raw)
+			isDir = isDirFramed(raw)
+			if isDir && outDirPath == "" && outFile == "" {
+				fmt.Fprintln(w, "  note: shares are from 'split -in-dir'; pass -out-dir and/or -out to recover the archive")
+			}
+		} else if len(raw) != rawLen {
+			fmt.Fprintf(w, "  rejected: length %d does not match previously entered shares (%d)\n", len(raw), rawLen)
+			continue
+		}
+
+		working := raw
+		if isDir {
+			if !isDirFramed(working) {
+				fmt.Fprintln(w, "  rejected: not a directory-archive share like the ones already entered")
+				continue
+			}
+			hash, n, share, err := unwrapDirFrame(working)
+			if err != nil {
+				fmt.Fprintf(w, "  rejected: %v\n", err)
+				continue
+			}
+			if !archiveSet {
+				archiveHash, archiveLen = hash, n
+				archiveSet = true
+			} else if hash != archiveHash || n != archiveLen {
+				fmt.Fprintln(w, "  rejected: directory-archive hash does not match previously entered shares (different split?)")
+				continue
+			}
+			// share is a subslice of raw (unwrapDirFrame doesn't copy), so
+			// raw can't be zeroized here without wiping share out from
+			// under it; it's scrubbed via plain/working at the end instead.
+			working = share
+		}
+
+		var tag byte
+		switch {
+		case isPassphraseFramed(working):
+			kdf, protected, err := unwrapPassphraseFrame(working)
+			if err != nil {
+				fmt.Fprintf(w, "  rejected: %v\n", err)
+				continue
+			}
+			if passphrase == "" {
+				fmt.Fprintln(w, "  rejected: share is passphrase-protected; restart with -passphrase")
+				continue
+			}
+			if shareOpts == nil {
+				aeadKey, tagOffset := kdf.DeriveKey([]byte(passphrase))
+				shareOpts = &shamir.Options{KDF: kdf, AEADKey: aeadKey, TagOffset: tagOffset}
+			}
+			// protected is a subslice of working (unwrapPassphraseFrame
+			// doesn't copy), so working can't be zeroized here either.
+			working = protected
+			tag = working[len(working)-1] ^ shareOpts.TagOffset
+
+		case isRSFramed(working):
+			payload, _, t, _, corrected, err := unwrapRSFrame(working, fix)
+			if err != nil {
+				fmt.Fprintf(w, "  rejected: %v\n", err)
+				continue
+			}
+			if corrected > 0 {
+				fmt.Fprintf(w, "  repaired %d byte(s) via Reed-Solomon\n", corrected)
+			}
+			if threshold == 0 {
+				threshold = t
+			}
+			zero(working)
+			working = payload
+			tag = working[len(working)-1]
+
+		default:
+			tag = working[len(working)-1]
+		}
+
+		if seenTags[tag] {
+			fmt.Fprintln(w, "  rejected: a share with this tag was already entered (duplicate or typo)")
+			continue
+		}
+		seenTags[tag] = true
+		plain = append(plain, working)
+
+		if threshold > 0 {
+			fmt.Fprintf(w, "  accepted. %d/%d shares collected.\n", len(plain), threshold)
+			if len(plain) == threshold {
+				fmt.Fprintln(w, "  Threshold reached: type 'combine' to reconstruct, or keep entering shares to cross-check.")
+			}
+		} else {
+			fmt.Fprintf(w, "  accepted. %d shares collected.\n", len(plain))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed reading input: %w", err)
+	}
+	return fmt.Errorf("input ended before 'combine' or 'done' was entered")
+}
+
+// finishInteractiveCombine reconstructs the secret from the entered shares,
+// then applies the same archive-integrity/-out/-out-dir handling as the
+// non-interactive combine path. If more than threshold shares were entered,
+// it also combines a different t-sized subset and returns an error if it disagrees, as
+// a stronger self-test than trusting a single combination.
+func finishInteractiveCombine(w io.Writer, plain [][]byte, opts *shamir.Options, threshold int, isDir bool, archiveHash [sha256.Size]byte, archiveLen int, outFile, outDirPath string) error {
+	secret, err := shamir.CombineWithOptions(plain, opts)
+	if err != nil {
+		return fmt.Errorf("combine failed: %w", err)
+	}
+	defer zero(secret)
+
+	if threshold > 0 && len(plain) > threshold {
+		altIdx := rand.Perm(len(plain))[:threshold]
+		alt := make([][]byte, threshold)
+		for i, idx := range altIdx {
+			alt[i] = plain[idx]
+		}
+		altSecret, err := shamir.CombineWithOptions(alt, opts)
+		if err != nil {
+			return fmt.Errorf("cross-check combine failed: %w", err)
+		}
+		defer zero(altSecret)
+		if !bytesEqual(secret, altSecret) {
+			return fmt.Errorf("cross-check failed: combining a different subset of entered shares produced a different secret (bad share among those entered)")
+		}
+		fmt.Fprintln(w, "Cross-check: PASS (an independent subset of entered shares reconstructed the same secret)")
+	}
+
+	if isDir {
+		if len(secret) != archiveLen || sha256.Sum256(secret) != archiveHash {
+			return fmt.Errorf("reconstructed archive failed its SHA-256 integrity check")
+		}
+		if outDirPath != "" {
+			if err := archive.Extract(secret, outDirPath); err != nil {
+				return fmt.Errorf("failed to unzip -out-dir: %w", err)
+			}
+			fmt.Fprintf(w, "Integrity check: PASS. Unzipped archive to %s\n", outDirPath)
+		} else {
+			fmt.Fprintln(w, "Integrity check: PASS (pass -out-dir to unzip the recovered archive)")
+		}
+		if outFile == "" {
+			return nil
+		}
+	}
+
+	if outFile != "" {
+		if runtime.GOOS == "windows" {
+			fmt.Fprintln(os.Stderr, "Warning: file mode 0600 is POSIX-only and not enforced on Windows; ensure directory ACLs are restrictive.")
+		}
+		if err := os.WriteFile(outFile, secret, 0o600); err != nil {
+			return fmt.Errorf("failed to write -out: %w", err)
+		}
+		fmt.Fprintf(w, "Recovered secret written to %s (%d bytes)\n", outFile, len(secret))
+		return nil
+	}
+
+	if !isLikelyText(secret) {
+		fmt.Fprintln(os.Stderr, "Warning: recovered data looks binary. Use -out to write to a file.")
+	}
+	fmt.Fprintf(w, "Combine OK. Recovered %d bytes.\n", len(secret))
+	fmt.Fprintf(w, "%s\n", string(secret))
+	return nil
+}
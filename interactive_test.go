@@ -0,0 +1,211 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	shamir "sss_cli/shamir"
+)
+
+func b64Lines(shares [][]byte, extra ...string) string {
+	var lines []string
+	for _, s := range shares {
+		lines = append(lines, base64.StdEncoding.EncodeToString(s))
+	}
+	lines = append(lines, extra...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestCmdCombineInteractiveHappyPath(t *testing.T) {
+	secret := []byte("interactive secret")
+	shares, err := shamir.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("shamir.Split: %v", err)
+	}
+
+	in := b64Lines(shares[:3], "combine")
+	var out strings.Builder
+	err = cmdCombineInteractive(strings.NewReader(in), &out, 3, "", false, "", "")
+	if err != nil {
+		t.Fatalf("cmdCombineInteractive: %v\noutput:\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), string(secret)) {
+		t.Fatalf("output does not contain recovered secret:\n%s", out.String())
+	}
+}
+
+func TestCmdCombineInteractiveDuplicateTagRejected(t *testing.T) {
+	secret := []byte("duplicate test")
+	shares, err := shamir.Split(secret, 5, 2)
+	if err != nil {
+		t.Fatalf("shamir.Split: %v", err)
+	}
+
+	// Enter the same share twice, then a different one, then combine.
+	in := b64Lines([][]byte{shares[0], shares[0], shares[1]}, "combine")
+	var out strings.Builder
+	err = cmdCombineInteractive(strings.NewReader(in), &out, 2, "", false, "", "")
+	if err != nil {
+		t.Fatalf("cmdCombineInteractive: %v\noutput:\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "duplicate or typo") {
+		t.Fatalf("expected a duplicate-tag rejection message, got:\n%s", out.String())
+	}
+}
+
+func TestCmdCombineInteractiveLengthMismatchRejected(t *testing.T) {
+	secret := []byte("length mismatch test")
+	shares, err := shamir.Split(secret, 5, 2)
+	if err != nil {
+		t.Fatalf("shamir.Split: %v", err)
+	}
+	bogus := append(append([]byte(nil), shares[0]...), 0xFF) // one byte longer
+
+	in := b64Lines([][]byte{shares[0], bogus, shares[1]}, "combine")
+	var out strings.Builder
+	err = cmdCombineInteractive(strings.NewReader(in), &out, 2, "", false, "", "")
+	if err != nil {
+		t.Fatalf("cmdCombineInteractive: %v\noutput:\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "does not match previously entered shares") {
+		t.Fatalf("expected a length-mismatch rejection message, got:\n%s", out.String())
+	}
+}
+
+func TestCmdCombineInteractiveRSFrameDispatch(t *testing.T) {
+	secret := []byte("rs framed secret")
+	shares, err := shamir.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("shamir.Split: %v", err)
+	}
+	var framed [][]byte
+	for i, s := range shares[:3] {
+		f, err := wrapRSFrame(s, 5, 3, i+1)
+		if err != nil {
+			t.Fatalf("wrapRSFrame: %v", err)
+		}
+		framed = append(framed, f)
+	}
+
+	// Threshold is picked up automatically from the RS frame (t=3), so pass 0.
+	in := b64Lines(framed, "combine")
+	var out strings.Builder
+	err = cmdCombineInteractive(strings.NewReader(in), &out, 0, "", false, "", "")
+	if err != nil {
+		t.Fatalf("cmdCombineInteractive: %v\noutput:\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), string(secret)) {
+		t.Fatalf("output does not contain recovered secret:\n%s", out.String())
+	}
+}
+
+func TestCmdCombineInteractivePassphraseFrameDispatch(t *testing.T) {
+	kdf, err := shamir.NewKDFParams(1, 8*1024, 1) // cheap params for test speed
+	if err != nil {
+		t.Fatalf("NewKDFParams: %v", err)
+	}
+	aeadKey, tagOffset := kdf.DeriveKey([]byte("hunter2"))
+	opts := &shamir.Options{KDF: kdf, AEADKey: aeadKey, TagOffset: tagOffset}
+
+	secret := []byte("passphrase framed secret")
+	shares, err := shamir.SplitWithOptions(secret, 5, 3, opts)
+	if err != nil {
+		t.Fatalf("SplitWithOptions: %v", err)
+	}
+	var framed [][]byte
+	for _, s := range shares[:3] {
+		framed = append(framed, wrapPassphraseFrame(s, kdf))
+	}
+
+	in := b64Lines(framed, "combine")
+	var out strings.Builder
+	err = cmdCombineInteractive(strings.NewReader(in), &out, 3, "hunter2", false, "", "")
+	if err != nil {
+		t.Fatalf("cmdCombineInteractive: %v\noutput:\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), string(secret)) {
+		t.Fatalf("output does not contain recovered secret:\n%s", out.String())
+	}
+}
+
+func TestCmdCombineInteractivePassphraseFrameWrongPassphraseRejected(t *testing.T) {
+	kdf, err := shamir.NewKDFParams(1, 8*1024, 1)
+	if err != nil {
+		t.Fatalf("NewKDFParams: %v", err)
+	}
+	aeadKey, tagOffset := kdf.DeriveKey([]byte("hunter2"))
+	opts := &shamir.Options{KDF: kdf, AEADKey: aeadKey, TagOffset: tagOffset}
+
+	shares, err := shamir.SplitWithOptions([]byte("secret"), 5, 3, opts)
+	if err != nil {
+		t.Fatalf("SplitWithOptions: %v", err)
+	}
+	var framed [][]byte
+	for _, s := range shares[:3] {
+		framed = append(framed, wrapPassphraseFrame(s, kdf))
+	}
+
+	in := b64Lines(framed, "combine")
+	var out strings.Builder
+	err = cmdCombineInteractive(strings.NewReader(in), &out, 3, "wrong passphrase", false, "", "")
+	if err == nil {
+		t.Fatalf("expected combine failed due to wrong passphrase, got success:\n%s", out.String())
+	}
+}
+
+func TestCmdCombineInteractiveCrossCheckMismatch(t *testing.T) {
+	secretA, err := shamir.Split([]byte("secret A is long"), 5, 2)
+	if err != nil {
+		t.Fatalf("shamir.Split: %v", err)
+	}
+	secretB, err := shamir.Split([]byte("secret B is long"), 5, 2)
+	if err != nil {
+		t.Fatalf("shamir.Split: %v", err)
+	}
+	// Mix two shares from one split with one from an unrelated split: same
+	// length (so it passes the length check) but a bad share among those
+	// entered, so combining different subsets should disagree. secretB[2]
+	// is picked (rather than secretB[0]) so its tag byte (3) doesn't
+	// collide with either of secretA's entered tags (1, 2).
+	mixed := [][]byte{secretA[0], secretA[1], secretB[2]}
+
+	in := b64Lines(mixed, "combine")
+	var out strings.Builder
+	err = cmdCombineInteractive(strings.NewReader(in), &out, 2, "", false, "", "")
+	if err == nil || !strings.Contains(err.Error(), "cross-check failed") {
+		t.Fatalf("expected a cross-check-mismatch error, got err=%v\noutput:\n%s", err, out.String())
+	}
+}
+
+func TestCmdCombineInteractiveDirFrameDispatch(t *testing.T) {
+	archive := []byte("pretend this is a zip archive's bytes")
+	archiveHash := sha256.Sum256(archive)
+	shares, err := shamir.Split(archive, 5, 3)
+	if err != nil {
+		t.Fatalf("shamir.Split: %v", err)
+	}
+	var framed [][]byte
+	for _, s := range shares[:3] {
+		framed = append(framed, wrapDirFrame(s, archiveHash, len(archive)))
+	}
+
+	in := b64Lines(framed, "combine")
+	var out strings.Builder
+	err = cmdCombineInteractive(strings.NewReader(in), &out, 3, "", false, "", "")
+	if err != nil {
+		t.Fatalf("cmdCombineInteractive: %v\noutput:\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "Integrity check: PASS") {
+		t.Fatalf("expected integrity check to pass, got:\n%s", out.String())
+	}
+}
+
+func TestCmdCombineInteractiveQuitAborts(t *testing.T) {
+	var out strings.Builder
+	err := cmdCombineInteractive(strings.NewReader("quit\n"), &out, 2, "", false, "", "")
+	if err == nil || !strings.Contains(err.Error(), "aborted") {
+		t.Fatalf("expected an aborted-by-user error, got %v", err)
+	}
+}
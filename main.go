@@ -2,6 +2,7 @@ package main
 
 import (
 	cryptoRand "crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"flag"
@@ -12,6 +13,7 @@ import (
 	"runtime"
 	"strings"
 
+	"sss_cli/archive"
 	shamir "sss_cli/shamir"
 )
 
@@ -48,28 +50,66 @@ func main() {
 func usage() {
 	fmt.Fprintf(os.Stderr, `
 Usage:
-  %s split   -n <parts> -t <threshold> [-secret <text>] [-in <file>] [-outdir <dir>] [--quiet]
-  %s combine (-shares <b64,b64,...> | -files <f1,f2,...>) [-out <file>]
+  %s split   -n <parts> -t <threshold> [-secret <text>|-in <file>|-in-dir <dir>] [-outdir <dir>] [--quiet]
+  %s combine (-shares <b64,b64,...> | -files <f1,f2,...> | -streams <f1,f2,...> | -qr-dir <dir> | -qr-files <f1,f2,...> | -interactive) [-out <file>] [-out-dir <dir>]
 
 Subcommands:
 
   split
     -n         Number of shares to generate (2..255)
     -t         Threshold required to reconstruct (2..<=n)
-    -secret    Secret provided as a UTF-8 string (mutually exclusive with -in)
-    -in        Path to a binary file containing the secret (mutually exclusive with -secret)
+    -secret    Secret provided as a UTF-8 string (mutually exclusive with -in/-in-dir)
+    -in        Path to a binary file containing the secret (mutually exclusive with -secret/-in-dir)
+    -in-dir    Path to a directory to pack into a deterministic ZIP archive and split
+               as a self-describing share archive (mutually exclusive with -secret/-in)
     -outdir    If set, writes shares as text files containing Base64 (share_01.b64, ...)
+    -rs        Wrap each share in a Reed-Solomon-protected frame so minor bit-rot
+               (bad OCR, a transcription typo) can be detected, and repaired with -fix
+    -passphrase
+               Stretch a passphrase with Argon2id to encrypt each share's payload
+               (ChaCha20-Poly1305) and bind shares to that passphrase (mutually
+               exclusive with -rs for now)
+    -argon-time, -argon-memory, -argon-threads
+               Configure the Argon2id time cost (iterations), memory cost (KiB),
+               and parallelism (lanes) used with -passphrase (default: 3, 64MiB, 4)
+    -chunk     Split -in in fixed-size chunks (e.g. 4MiB, 64KiB) as streamed share
+               files (share_01.sss, ..., share_NN.sss) instead of one in-memory
+               split; for secrets too large to comfortably fit in memory
+    -qr        Render each share as QR code PNG(s) in this directory, for paper
+               backup; shares too large for one QR are split across numbered pages
     --quiet    If set, suppresses printing shares to stdout
     (The program performs a quick self-test by recombining a random threshold-sized subset.)
 
   combine
     -shares    Comma-separated Base64 shares (each one is a single share)
     -files     Comma-separated file paths; each file contains a single Base64 share
+    -streams   Comma-separated 'split -chunk' share stream files; reconstructs
+               chunk-by-chunk and requires -out (incompatible with -shares/-files)
+    -qr-dir    Directory of 'split -qr' share QR code PNGs to scan and reassemble
+    -qr-files  Comma-separated 'split -qr' share QR code PNGs, as an alternative to -qr-dir
+    -fix       Attempt Reed-Solomon repair of corrupted RS-framed shares instead of
+               rejecting them; reports how many bytes were corrected per share
+    -passphrase
+               Passphrase used at split time for -passphrase-protected shares;
+               required if the shares are passphrase-framed
+    -out-dir   Unzip a recovered 'split -in-dir' archive into this directory after
+               verifying its SHA-256 (rejects path-traversal/symlink zip entries)
     -out       If set, writes the recovered secret to this file; otherwise prints as text
                (If the original secret was binary, you should use -out.)
+    -interactive
+               Prompt for shares one at a time (Base64, or a path to a file containing
+               one), validating each on entry and reporting 'k/t shares collected'.
+               Once enough are in, combines; if the threshold is known and more than
+               that many shares were entered, also cross-checks against a different
+               threshold-sized subset (incompatible with -shares/-files/-streams/-qr-*)
+    -t         Threshold hint for -interactive progress reporting (optional; RS-framed
+               shares report their own threshold automatically)
 
 Notes:
   * Shares are Base64-encoded. Each share decodes to len(secret)+1 bytes (Shamir tag + data).
+  * RS-framed, passphrase-framed, and directory-archive shares are longer than that (they
+    add a frame header, plus RS parity or an AEAD tag), and are all auto-detected via
+    magic prefixes, so plain shares from before these features still combine unchanged.
   * For binary secrets, use 'split -in <file>' and 'combine -out <file>'.
 `, filepath.Base(os.Args[0]), filepath.Base(os.Args[0]))
 }
@@ -93,12 +133,20 @@ func zero2D(bb [][]byte) {
 func cmdSplit(args []string) error {
 	fs := flag.NewFlagSet("split", flag.ExitOnError)
 	var (
-		parts     = fs.Int("n", 0, "number of shares")
-		threshold = fs.Int("t", 0, "threshold to reconstruct")
-		text      = fs.String("secret", "", "secret as text (UTF-8)")
-		inFile    = fs.String("in", "", "path to binary secret file")
-		outDir    = fs.String("outdir", "", "directory to write Base64 shares")
-		quiet     = fs.Bool("quiet", false, "suppress printing shares to stdout")
+		parts        = fs.Int("n", 0, "number of shares")
+		threshold    = fs.Int("t", 0, "threshold to reconstruct")
+		text         = fs.String("secret", "", "secret as text (UTF-8)")
+		inFile       = fs.String("in", "", "path to binary secret file")
+		inDir        = fs.String("in-dir", "", "path to a directory to pack and split as a share archive")
+		outDir       = fs.String("outdir", "", "directory to write Base64 shares")
+		rsProtect    = fs.Bool("rs", false, "wrap each share in a Reed-Solomon-protected frame")
+		passphrase   = fs.String("passphrase", "", "stretch a passphrase (Argon2id) to encrypt shares and bind them together")
+		argonTime    = fs.Uint("argon-time", 3, "Argon2id time cost (iterations); only used with -passphrase")
+		argonMemory  = fs.Uint("argon-memory", 64*1024, "Argon2id memory cost in KiB; only used with -passphrase")
+		argonThreads = fs.Uint("argon-threads", 4, "Argon2id parallelism (lanes); only used with -passphrase")
+		chunkSize    = fs.String("chunk", "", "split -in in fixed-size chunks (e.g. 4MiB) as streamed share files, for secrets too large to hold in memory")
+		qrDir        = fs.String("qr", "", "render each share as QR code PNG(s) in this directory, for paper backup")
+		quiet        = fs.Bool("quiet", false, "suppress printing shares to stdout")
 	)
 	_ = fs.Parse(args)
 
@@ -106,30 +154,75 @@ func cmdSplit(args []string) error {
 	if *parts < *threshold || *parts < 2 || *threshold < 2 || *parts > 255 || *threshold > 255 {
 		return fmt.Errorf("invalid -n / -t values: require 2 <= t <= n <= 255")
 	}
-	if (*text == "" && *inFile == "") || (*text != "" && *inFile != "") {
-		return fmt.Errorf("provide exactly one of -secret or -in")
+
+	if *chunkSize != "" {
+		if *inFile == "" || *text != "" || *inDir != "" {
+			return fmt.Errorf("-chunk requires -in and is incompatible with -secret/-in-dir")
+		}
+		if *rsProtect || *passphrase != "" || *qrDir != "" {
+			return fmt.Errorf("-chunk cannot be combined with -rs, -passphrase, or -qr yet")
+		}
+		size, err := parseChunkSize(*chunkSize)
+		if err != nil {
+			return err
+		}
+		return cmdSplitChunked(*inFile, *outDir, *parts, *threshold, size)
+	}
+
+	inputCount := 0
+	for _, set := range []bool{*text != "", *inFile != "", *inDir != ""} {
+		if set {
+			inputCount++
+		}
+	}
+	if inputCount != 1 {
+		return fmt.Errorf("provide exactly one of -secret, -in, or -in-dir")
+	}
+	if *rsProtect && *passphrase != "" {
+		return fmt.Errorf("-rs and -passphrase cannot be combined yet")
 	}
 
 	// Load secret
 	var secret []byte
 	var err error
-	if *text != "" {
+	var archiveHash [sha256.Size]byte
+	isDirSplit := *inDir != ""
+	switch {
+	case *text != "":
 		// NOTE: using []byte(string) makes an immutable string copy first; here
 		// we accept it because input came from argv. We zeroize our slice.
 		secret = []byte(*text)
-	} else {
+	case *inFile != "":
 		secret, err = os.ReadFile(*inFile)
 		if err != nil {
 			return fmt.Errorf("failed to read -in: %w", err)
 		}
+	default:
+		secret, err = archive.Build(*inDir)
+		if err != nil {
+			return fmt.Errorf("failed to pack -in-dir: %w", err)
+		}
+		archiveHash = sha256.Sum256(secret)
 	}
 	if len(secret) == 0 {
 		return errors.New("secret is empty")
 	}
 	defer zero(secret)
 
-	// Split
-	shares, err := shamir.Split(secret, *parts, *threshold)
+	// Split, optionally under Argon2id-derived encryption/tag-remapping.
+	var kdf *shamir.KDFParams
+	var shareOpts *shamir.Options
+	if *passphrase != "" {
+		kdf, err = shamir.NewKDFParams(uint32(*argonTime), uint32(*argonMemory), uint8(*argonThreads))
+		if err != nil {
+			return fmt.Errorf("failed to initialize KDF: %w", err)
+		}
+		aeadKey, tagOffset := kdf.DeriveKey([]byte(*passphrase))
+		defer zero(aeadKey)
+		shareOpts = &shamir.Options{KDF: kdf, AEADKey: aeadKey, TagOffset: tagOffset}
+	}
+
+	shares, err := shamir.SplitWithOptions(secret, *parts, *threshold, shareOpts)
 	if err != nil {
 		return fmt.Errorf("split failed: %w", err)
 	}
@@ -137,17 +230,48 @@ func cmdSplit(args []string) error {
 	defer zero2D(shares)
 
 	// Optional self-test: pick random threshold shares and try combine
-	ok, testErr := selfTestCombine(shares, *threshold, secret)
+	ok, testErr := selfTestCombine(shares, *threshold, secret, shareOpts)
 	if !ok {
 		return fmt.Errorf("self-test failed: %v", testErr)
 	}
 
+	// Wrap shares in frames *after* the self-test above, which exercises
+	// the underlying Shamir (+ AEAD) math directly; framing only affects
+	// how shares are carried, not how they combine.
+	outShares := shares
+	if shareOpts != nil {
+		framed := make([][]byte, len(shares))
+		for i, s := range shares {
+			framed[i] = wrapPassphraseFrame(s, kdf)
+		}
+		outShares = framed
+	} else if *rsProtect {
+		framed := make([][]byte, len(shares))
+		for i, s := range shares {
+			f, err := wrapRSFrame(s, *parts, *threshold, i+1)
+			if err != nil {
+				return fmt.Errorf("failed to RS-frame share %d: %w", i+1, err)
+			}
+			framed[i] = f
+		}
+		defer zero2D(framed)
+		outShares = framed
+	}
+
+	if isDirSplit {
+		framed := make([][]byte, len(outShares))
+		for i, s := range outShares {
+			framed[i] = wrapDirFrame(s, archiveHash, len(secret))
+		}
+		outShares = framed
+	}
+
 	// Output: stdout (unless quiet) and/or files.
 	// Avoid materializing Base64 as strings; stream instead.
 
 	if !*quiet {
 		fmt.Println("Shares (Base64):")
-		for i, s := range shares {
+		for i, s := range outShares {
 			fmt.Printf("  [%02d] ", i+1)
 			enc := base64.NewEncoder(base64.StdEncoding, os.Stdout)
 			if _, err := enc.Write(s); err != nil {
@@ -168,7 +292,7 @@ func cmdSplit(args []string) error {
 		if runtime.GOOS == "windows" {
 			fmt.Fprintln(os.Stderr, "Warning: file mode 0600 is POSIX-only and not enforced on Windows; ensure directory ACLs are restrictive.")
 		}
-		for i, s := range shares {
+		for i, s := range outShares {
 			name := filepath.Join(*outDir, fmt.Sprintf("share_%02d.b64", i+1))
 			f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
 			if err != nil {
@@ -188,11 +312,23 @@ func cmdSplit(args []string) error {
 				return fmt.Errorf("failed to close %s: %w", name, err)
 			}
 		}
-		fmt.Printf("Wrote %d share files to %s\n", len(shares), *outDir)
+		fmt.Printf("Wrote %d share files to %s\n", len(outShares), *outDir)
+	}
+
+	if *qrDir != "" {
+		if err := os.MkdirAll(*qrDir, 0o700); err != nil {
+			return fmt.Errorf("failed to create -qr directory: %w", err)
+		}
+		for i, s := range outShares {
+			if err := writeShareQRCodes(*qrDir, i+1, s); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Wrote QR code PNGs for %d shares to %s\n", len(outShares), *qrDir)
 	}
 
 	// Compute lengths without creating Base64 strings
-	rawLen := len(shares[0])
+	rawLen := len(outShares[0])
 	b64Len := base64.StdEncoding.EncodedLen(rawLen)
 	fmt.Printf("Split OK. n=%d t=%d; share length=%d bytes (raw), %d Base64 chars.\n",
 		*parts, *threshold, rawLen, b64Len)
@@ -201,7 +337,7 @@ func cmdSplit(args []string) error {
 }
 
 // selfTestCombine takes a random threshold-sized subset, combines, and checks equality.
-func selfTestCombine(all [][]byte, threshold int, original []byte) (bool, error) {
+func selfTestCombine(all [][]byte, threshold int, original []byte, opts *shamir.Options) (bool, error) {
 	idxs, err := randomDistinctIndices(len(all), threshold)
 	if err != nil {
 		return false, err
@@ -210,7 +346,7 @@ func selfTestCombine(all [][]byte, threshold int, original []byte) (bool, error)
 	for _, i := range idxs {
 		sub = append(sub, all[i])
 	}
-	rec, err := shamir.Combine(sub)
+	rec, err := shamir.CombineWithOptions(sub, opts)
 	if err != nil {
 		return false, err
 	}
@@ -247,19 +383,60 @@ func randomDistinctIndices(n, k int) ([]int, error) {
 func cmdCombine(args []string) error {
 	fs := flag.NewFlagSet("combine", flag.ExitOnError)
 	var (
-		shareCSV = fs.String("shares", "", "comma-separated Base64 shares")
-		filesCSV = fs.String("files", "", "comma-separated files (each contains one Base64 share)")
-		outFile  = fs.String("out", "", "write recovered secret to file (recommended for binary)")
+		shareCSV    = fs.String("shares", "", "comma-separated Base64 shares")
+		filesCSV    = fs.String("files", "", "comma-separated files (each contains one Base64 share)")
+		fix         = fs.Bool("fix", false, "attempt Reed-Solomon repair of corrupted RS-framed shares")
+		passphrase  = fs.String("passphrase", "", "passphrase used to encrypt the shares at split time")
+		outFile     = fs.String("out", "", "write recovered secret to file (recommended for binary)")
+		outDirPath  = fs.String("out-dir", "", "unzip a -in-dir share archive into this directory after combining")
+		streamsCSV  = fs.String("streams", "", "comma-separated 'split -chunk' share stream files (share_NN.sss)")
+		qrDirPath   = fs.String("qr-dir", "", "directory of 'split -qr' share QR code PNGs to scan")
+		qrFilesCSV  = fs.String("qr-files", "", "comma-separated 'split -qr' share QR code PNGs to scan")
+		interactive = fs.Bool("interactive", false, "prompt for shares one at a time, reporting threshold progress as you go")
+		threshold   = fs.Int("t", 0, "threshold (optional hint for -interactive progress; RS-framed shares report it automatically)")
 	)
 	_ = fs.Parse(args)
 
-	if (*shareCSV == "" && *filesCSV == "") || (*shareCSV != "" && *filesCSV != "") {
-		return fmt.Errorf("provide exactly one of -shares or -files")
+	if *interactive {
+		if *shareCSV != "" || *filesCSV != "" || *streamsCSV != "" || *qrDirPath != "" || *qrFilesCSV != "" {
+			return fmt.Errorf("-interactive is incompatible with -shares/-files/-streams/-qr-dir/-qr-files")
+		}
+		return cmdCombineInteractive(os.Stdin, os.Stdout, *threshold, *passphrase, *fix, *outFile, *outDirPath)
+	}
+
+	if *streamsCSV != "" {
+		if *shareCSV != "" || *filesCSV != "" {
+			return fmt.Errorf("-streams is incompatible with -shares/-files")
+		}
+		return cmdCombineChunked(splitCSV(*streamsCSV), *outFile)
+	}
+
+	usingQR := *qrDirPath != "" || *qrFilesCSV != ""
+	if usingQR && (*shareCSV != "" || *filesCSV != "") {
+		return fmt.Errorf("-qr-dir/-qr-files is incompatible with -shares/-files")
+	}
+	if !usingQR && ((*shareCSV == "") == (*filesCSV == "")) {
+		return fmt.Errorf("provide exactly one of -shares, -files, or -qr-dir/-qr-files")
 	}
 
 	var parts [][]byte
 
-	if *filesCSV != "" {
+	if usingQR {
+		var pngPaths []string
+		var err error
+		if *qrDirPath != "" {
+			pngPaths, err = listPNGs(*qrDirPath)
+		} else {
+			pngPaths = splitCSV(*qrFilesCSV)
+		}
+		if err != nil {
+			return err
+		}
+		parts, err = readQRShares(pngPaths)
+		if err != nil {
+			return fmt.Errorf("failed to read QR shares: %w", err)
+		}
+	} else if *filesCSV != "" {
 		paths := splitCSV(*filesCSV)
 		for _, p := range paths {
 			data, err := os.ReadFile(p)
@@ -301,12 +478,81 @@ func cmdCombine(args []string) error {
 	}
 	defer zero2D(parts)
 
-	secret, err := shamir.Combine(parts)
+	isDirCombine := isDirFramed(parts[0])
+	var archiveHash [sha256.Size]byte
+	var archiveLen int
+	if isDirCombine {
+		for i, raw := range parts {
+			hash, n, share, err := unwrapDirFrame(raw)
+			if err != nil {
+				return fmt.Errorf("share %d: %w", i+1, err)
+			}
+			if i == 0 {
+				archiveHash, archiveLen = hash, n
+			}
+			parts[i] = share
+		}
+	} else if *outDirPath != "" {
+		return fmt.Errorf("-out-dir requires shares produced by 'split -in-dir'")
+	}
+
+	var shareOpts *shamir.Options
+	if isPassphraseFramed(parts[0]) {
+		if *passphrase == "" {
+			return fmt.Errorf("shares are passphrase-protected; pass -passphrase")
+		}
+		for i, raw := range parts {
+			kdf, protected, err := unwrapPassphraseFrame(raw)
+			if err != nil {
+				return fmt.Errorf("share %d: %w", i+1, err)
+			}
+			if shareOpts == nil {
+				aeadKey, tagOffset := kdf.DeriveKey([]byte(*passphrase))
+				defer zero(aeadKey)
+				shareOpts = &shamir.Options{KDF: kdf, AEADKey: aeadKey, TagOffset: tagOffset}
+			}
+			parts[i] = protected
+		}
+	} else {
+		for i, raw := range parts {
+			if !isRSFramed(raw) {
+				continue // legacy bare share: combine unchanged
+			}
+			payload, _, _, _, corrected, err := unwrapRSFrame(raw, *fix)
+			if err != nil {
+				return fmt.Errorf("share %d: %w", i+1, err)
+			}
+			if corrected > 0 {
+				fmt.Fprintf(os.Stderr, "Repaired %d byte(s) in share %d\n", corrected, i+1)
+			}
+			zero(raw)
+			parts[i] = payload
+		}
+	}
+
+	secret, err := shamir.CombineWithOptions(parts, shareOpts)
 	if err != nil {
 		return fmt.Errorf("combine failed: %w", err)
 	}
 	defer zero(secret)
 
+	if isDirCombine {
+		if len(secret) != archiveLen || sha256.Sum256(secret) != archiveHash {
+			return errors.New("reconstructed archive failed its SHA-256 integrity check")
+		}
+		if *outDirPath != "" {
+			if err := archive.Extract(secret, *outDirPath); err != nil {
+				return fmt.Errorf("failed to unzip -out-dir: %w", err)
+			}
+			fmt.Printf("Integrity check: PASS. Unzipped archive to %s\n", *outDirPath)
+		} else {
+			fmt.Println("Integrity check: PASS (pass -out-dir to unzip the recovered archive)")
+		}
+		if *outFile == "" {
+			return nil
+		}
+	}
+
 	if *outFile != "" {
 		if runtime.GOOS == "windows" {
 			fmt.Fprintln(os.Stderr, "Warning: file mode 0600 is POSIX-only and not enforced on Windows; ensure directory ACLs are restrictive.")
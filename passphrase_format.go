@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	shamir "sss_cli/shamir"
+)
+
+// -------------------- passphrase-protected share framing --------------------
+//
+// `-passphrase` on split stretches a user-supplied passphrase with Argon2id
+// and uses the derived key to both encrypt each share's payload
+// (ChaCha20-Poly1305) and remap its tag byte, so shares produced under
+// different passphrases (or belonging to a different split entirely) can't
+// be accidentally mixed together. The KDF salt and parameters travel in a
+// small frame header; the passphrase itself never does.
+
+var passphraseFrameMagic = [4]byte{'S', 'S', 'S', 'P'}
+
+const passphraseFrameVersion = 1
+
+// isPassphraseFramed reports whether b begins with the passphrase frame
+// magic prefix.
+func isPassphraseFramed(b []byte) bool {
+	return len(b) >= 4 && b[0] == passphraseFrameMagic[0] && b[1] == passphraseFrameMagic[1] && b[2] == passphraseFrameMagic[2] && b[3] == passphraseFrameMagic[3]
+}
+
+// wrapPassphraseFrame takes a share already encrypted/remapped by
+// shamir.SplitWithOptions and prefixes it with a header carrying the KDF
+// parameters and salt needed to reverse the process; it does no encryption
+// itself.
+func wrapPassphraseFrame(protectedShare []byte, kdf *shamir.KDFParams) []byte {
+	out := make([]byte, 0, 4+1+4+4+1+1+len(kdf.Salt)+len(protectedShare))
+	out = append(out, passphraseFrameMagic[:]...)
+	out = append(out, passphraseFrameVersion)
+	out = binary.BigEndian.AppendUint32(out, kdf.Time)
+	out = binary.BigEndian.AppendUint32(out, kdf.Memory)
+	out = append(out, kdf.Threads, byte(len(kdf.Salt)))
+	out = append(out, kdf.Salt...)
+	out = append(out, protectedShare...)
+	return out
+}
+
+// unwrapPassphraseFrame parses the header back out, returning the KDF
+// parameters (salt included) and the still-encrypted share payload.
+func unwrapPassphraseFrame(frame []byte) (kdf *shamir.KDFParams, protectedShare []byte, err error) {
+	if !isPassphraseFramed(frame) {
+		return nil, nil, errors.New("passphrase frame: missing magic prefix")
+	}
+	if len(frame) < 4+1+4+4+1+1 {
+		return nil, nil, errors.New("passphrase frame: truncated header")
+	}
+	if frame[4] != passphraseFrameVersion {
+		return nil, nil, fmt.Errorf("passphrase frame: unsupported version %d", frame[4])
+	}
+	time := binary.BigEndian.Uint32(frame[5:9])
+	memory := binary.BigEndian.Uint32(frame[9:13])
+	threads := frame[13]
+	saltLen := int(frame[14])
+
+	rest := frame[15:]
+	if len(rest) < saltLen {
+		return nil, nil, errors.New("passphrase frame: truncated salt")
+	}
+	salt := append([]byte(nil), rest[:saltLen]...)
+
+	return &shamir.KDFParams{Time: time, Memory: memory, Threads: threads, Salt: salt}, rest[saltLen:], nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	shamir "sss_cli/shamir"
+)
+
+func TestPassphraseFrameRoundTrip(t *testing.T) {
+	kdf, err := shamir.NewKDFParams(3, 64*1024, 4)
+	if err != nil {
+		t.Fatalf("NewKDFParams: %v", err)
+	}
+	protectedShare := []byte("ciphertext + tag + remapped index byte")
+
+	frame := wrapPassphraseFrame(protectedShare, kdf)
+	if !isPassphraseFramed(frame) {
+		t.Fatalf("wrapPassphraseFrame output is not recognized by isPassphraseFramed")
+	}
+
+	gotKDF, gotShare, err := unwrapPassphraseFrame(frame)
+	if err != nil {
+		t.Fatalf("unwrapPassphraseFrame: %v", err)
+	}
+	if gotKDF.Time != kdf.Time || gotKDF.Memory != kdf.Memory || gotKDF.Threads != kdf.Threads {
+		t.Fatalf("unwrapPassphraseFrame KDF params = %+v, want %+v", gotKDF, kdf)
+	}
+	if !bytes.Equal(gotKDF.Salt, kdf.Salt) {
+		t.Fatalf("unwrapPassphraseFrame salt = %x, want %x", gotKDF.Salt, kdf.Salt)
+	}
+	if !bytes.Equal(gotShare, protectedShare) {
+		t.Fatalf("unwrapPassphraseFrame share = %q, want %q", gotShare, protectedShare)
+	}
+}
+
+func TestUnwrapPassphraseFrameRejectsBadVersion(t *testing.T) {
+	kdf, err := shamir.NewKDFParams(3, 64*1024, 4)
+	if err != nil {
+		t.Fatalf("NewKDFParams: %v", err)
+	}
+	frame := wrapPassphraseFrame([]byte("share"), kdf)
+	frame[4] = passphraseFrameVersion + 1
+	if _, _, err := unwrapPassphraseFrame(frame); err == nil {
+		t.Fatalf("unwrapPassphraseFrame should reject an unsupported version")
+	}
+}
+
+func TestUnwrapPassphraseFrameRejectsTruncatedHeader(t *testing.T) {
+	frame := append(append([]byte{}, passphraseFrameMagic[:]...), passphraseFrameVersion, 0, 0, 0)
+	if _, _, err := unwrapPassphraseFrame(frame); err == nil {
+		t.Fatalf("unwrapPassphraseFrame should reject a truncated header")
+	}
+}
+
+func TestUnwrapPassphraseFrameRejectsTruncatedSalt(t *testing.T) {
+	kdf, err := shamir.NewKDFParams(3, 64*1024, 4)
+	if err != nil {
+		t.Fatalf("NewKDFParams: %v", err)
+	}
+	frame := wrapPassphraseFrame([]byte("share"), kdf)
+	// Truncate the frame in the middle of the salt, after the header claims
+	// a full-length salt follows.
+	truncated := frame[:4+1+4+4+1+1+len(kdf.Salt)-1]
+	if _, _, err := unwrapPassphraseFrame(truncated); err == nil {
+		t.Fatalf("unwrapPassphraseFrame should reject a truncated salt")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sss_cli/qrshare"
+)
+
+// -------------------- QR code output/input --------------------
+//
+// `split -qr <dir>` renders each share as one or more QR code PNGs, for
+// printing on paper. `combine -qr-dir <dir>` / `-qr-files a.png,b.png,...`
+// scans them back in, regardless of order, and feeds the reassembled
+// shares into the normal combine path (so -fix, -passphrase, etc. all
+// still apply to whatever the QR codes carried).
+
+// writeShareQRCodes renders share (share index i, 1-based) as one or more
+// PNG pages under dir, named share_NN_pageP-of-T.png.
+func writeShareQRCodes(dir string, shareIndex int, share []byte) error {
+	pngs, err := qrshare.EncodePages(share, byte(shareIndex))
+	if err != nil {
+		return fmt.Errorf("failed to render share %d as QR: %w", shareIndex, err)
+	}
+	for page, png := range pngs {
+		name := filepath.Join(dir, fmt.Sprintf("share_%02d_page%d-of-%d.png", shareIndex, page+1, len(pngs)))
+		if err := os.WriteFile(name, png, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// readQRShares loads every PNG in paths and reassembles them into their
+// original shares (one []byte per distinct share ID found).
+func readQRShares(paths []string) ([][]byte, error) {
+	pngs := make([][]byte, len(paths))
+	for i, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		pngs[i] = data
+	}
+
+	byID, err := qrshare.Reassemble(pngs)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([][]byte, 0, len(byID))
+	for _, s := range byID {
+		shares = append(shares, s)
+	}
+	return shares, nil
+}
+
+// listPNGs returns every *.png file directly inside dir, for -qr-dir.
+func listPNGs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read -qr-dir %s: %w", dir, err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".png" {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, e.Name()))
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .png files found in %s", dir)
+	}
+	return paths, nil
+}
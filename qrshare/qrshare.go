@@ -0,0 +1,154 @@
+// Package qrshare renders Shamir shares as QR code PNGs (and reads them back)
+// so shares can be printed on paper instead of transcribed as 300+ character
+// Base64 strings. A share that doesn't fit in one QR code is split across
+// multiple numbered pages, each carrying a small header so a scanner can
+// reassemble them regardless of the order they're scanned in.
+package qrshare
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	_ "image/png" // registers the PNG decoder used by image.Decode
+	"sort"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	goqr "github.com/skip2/go-qrcode"
+)
+
+// MaxPagePayload is the number of share-payload bytes per QR page. This is
+// comfortably inside a version-40 QR code's byte-mode capacity even at the
+// high error-correction level EncodePages uses, leaving headroom for the
+// page header.
+const MaxPagePayload = 700
+
+const headerSize = 1 + 1 + 1 + 4 // shareID | page | total | crc32
+
+// EncodePages splits a share's raw bytes into one or more pages (each
+// carrying a header of shareID, page index, total page count, and a CRC32
+// of the payload) and renders each page as a QR code PNG.
+func EncodePages(share []byte, shareID byte) ([][]byte, error) {
+	if len(share) == 0 {
+		return nil, fmt.Errorf("qrshare: share is empty")
+	}
+	total := (len(share) + MaxPagePayload - 1) / MaxPagePayload
+	if total > 255 {
+		return nil, fmt.Errorf("qrshare: share needs %d pages, more than 255 supported", total)
+	}
+
+	pngs := make([][]byte, total)
+	for page := 0; page < total; page++ {
+		start := page * MaxPagePayload
+		end := start + MaxPagePayload
+		if end > len(share) {
+			end = len(share)
+		}
+		payload := share[start:end]
+
+		frame := make([]byte, 0, headerSize+len(payload))
+		frame = append(frame, shareID, byte(page), byte(total))
+		frame = binary.BigEndian.AppendUint32(frame, crc32.ChecksumIEEE(payload))
+		frame = append(frame, payload...)
+
+		png, err := goqr.Encode(string(frame), goqr.High, 512)
+		if err != nil {
+			return nil, fmt.Errorf("qrshare: failed to encode QR for share %d page %d: %w", shareID, page, err)
+		}
+		pngs[page] = png
+	}
+	return pngs, nil
+}
+
+// DecodeImage reads a single QR code PNG and returns its raw frame bytes.
+//
+// It reads the decoded byte segments from the result metadata rather than
+// GetText(): our frames are raw binary (CRC32, share bytes), and GetText()
+// round-trips byte-mode content through a guessed text charset (typically
+// ISO-8859-1), which is lossy for arbitrary bytes once converted back via a
+// Go (UTF-8) string. The BYTE_SEGMENTS metadata holds the undecoded bytes.
+func DecodeImage(png []byte) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(png))
+	if err != nil {
+		return nil, fmt.Errorf("qrshare: failed to decode image: %w", err)
+	}
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return nil, fmt.Errorf("qrshare: failed to binarize image: %w", err)
+	}
+	result, err := qrcode.NewQRCodeReader().Decode(bmp, nil)
+	if err != nil {
+		return nil, fmt.Errorf("qrshare: failed to read QR code: %w", err)
+	}
+	segments, ok := result.GetResultMetadata()[gozxing.ResultMetadataType_BYTE_SEGMENTS].([][]byte)
+	if !ok {
+		return nil, fmt.Errorf("qrshare: QR code has no byte-mode segments")
+	}
+	var frame []byte
+	for _, seg := range segments {
+		frame = append(frame, seg...)
+	}
+	return frame, nil
+}
+
+// page is one decoded-and-verified frame, ready to be reassembled.
+type page struct {
+	shareID byte
+	index   int
+	total   int
+	payload []byte
+}
+
+func parseFrame(frame []byte) (page, error) {
+	if len(frame) < headerSize {
+		return page{}, fmt.Errorf("qrshare: frame too short (%d bytes)", len(frame))
+	}
+	shareID := frame[0]
+	idx := int(frame[1])
+	total := int(frame[2])
+	wantCRC := binary.BigEndian.Uint32(frame[3:7])
+	payload := frame[7:]
+	if got := crc32.ChecksumIEEE(payload); got != wantCRC {
+		return page{}, fmt.Errorf("qrshare: CRC mismatch for share %d page %d/%d", shareID, idx+1, total)
+	}
+	return page{shareID: shareID, index: idx, total: total, payload: payload}, nil
+}
+
+// Reassemble decodes a set of QR code PNGs (in any order, possibly
+// interleaving pages from multiple shares) and returns each share's
+// reconstructed bytes keyed by its share ID. It errors if any share is
+// missing pages.
+func Reassemble(pngs [][]byte) (map[byte][]byte, error) {
+	byShare := map[byte][]page{}
+	for i, png := range pngs {
+		frame, err := DecodeImage(png)
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+		p, err := parseFrame(frame)
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %w", i, err)
+		}
+		byShare[p.shareID] = append(byShare[p.shareID], p)
+	}
+
+	out := make(map[byte][]byte, len(byShare))
+	for shareID, pages := range byShare {
+		sort.Slice(pages, func(i, j int) bool { return pages[i].index < pages[j].index })
+		total := pages[0].total
+		if len(pages) != total {
+			return nil, fmt.Errorf("qrshare: share %d has %d of %d pages", shareID, len(pages), total)
+		}
+		var buf bytes.Buffer
+		for i, p := range pages {
+			if p.index != i {
+				return nil, fmt.Errorf("qrshare: share %d is missing page %d", shareID, i+1)
+			}
+			buf.Write(p.payload)
+		}
+		out[shareID] = buf.Bytes()
+	}
+	return out, nil
+}
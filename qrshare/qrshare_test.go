@@ -0,0 +1,56 @@
+package qrshare
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodePagesReassembleRoundTrip(t *testing.T) {
+	share := bytes.Repeat([]byte("share-bytes-"), 100) // forces multiple pages
+	pngs, err := EncodePages(share, 42)
+	if err != nil {
+		t.Fatalf("EncodePages: %v", err)
+	}
+	if len(pngs) < 2 {
+		t.Fatalf("test share should span multiple pages, got %d", len(pngs))
+	}
+
+	got, err := Reassemble(pngs)
+	if err != nil {
+		t.Fatalf("Reassemble: %v", err)
+	}
+	if !bytes.Equal(got[42], share) {
+		t.Fatalf("Reassemble = %q, want %q", got[42], share)
+	}
+}
+
+func TestReassembleOutOfOrder(t *testing.T) {
+	share := bytes.Repeat([]byte("x"), MaxPagePayload+1)
+	pngs, err := EncodePages(share, 7)
+	if err != nil {
+		t.Fatalf("EncodePages: %v", err)
+	}
+	if len(pngs) != 2 {
+		t.Fatalf("expected exactly 2 pages, got %d", len(pngs))
+	}
+
+	reversed := [][]byte{pngs[1], pngs[0]}
+	got, err := Reassemble(reversed)
+	if err != nil {
+		t.Fatalf("Reassemble: %v", err)
+	}
+	if !bytes.Equal(got[7], share) {
+		t.Fatalf("Reassemble out of order = %q, want %q", got[7], share)
+	}
+}
+
+func TestReassembleMissingPageFails(t *testing.T) {
+	share := bytes.Repeat([]byte("y"), MaxPagePayload+1)
+	pngs, err := EncodePages(share, 1)
+	if err != nil {
+		t.Fatalf("EncodePages: %v", err)
+	}
+	if _, err := Reassemble(pngs[:1]); err == nil {
+		t.Fatalf("Reassemble should fail when a page is missing")
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"sss_cli/rscode"
+)
+
+// -------------------- RS-protected share framing --------------------
+//
+// `-rs` on split wraps each raw Shamir share in a small self-describing
+// frame so that a handful of flipped bytes (bad OCR, a transcription typo,
+// bit-rot on paper/QR) can be detected and, within the RS code's distance,
+// repaired before the bytes are ever fed into Lagrange interpolation.
+//
+// Frame layout:
+//
+//	magic(4) | version(1) | n(1) | t(1) | index(1) | payloadLen(4, BE) | RS blocks...
+//
+// The payload (the raw Shamir share) is split into fixed-size data blocks,
+// each protected by its own systematic GF(256) RS codeword. Plain shares
+// (no magic prefix) are still accepted on combine for backward
+// compatibility with shares produced before this feature existed.
+
+var rsFrameMagic = [4]byte{'S', 'S', 'S', 'R'}
+
+const (
+	rsFrameVersion  = 1
+	rsDataBlockSize = 128 // data bytes per RS block
+	rsParitySize    = 32  // parity bytes per RS block; corrects up to 16 byte errors
+	rsHeaderSize    = 4 + 1 + 1 + 1 + 1 + 4
+)
+
+func rsBlockCodec() (*rscode.Codec, error) {
+	return rscode.NewCodec(rsDataBlockSize, rsParitySize)
+}
+
+// isRSFramed reports whether b begins with the RS frame magic prefix.
+func isRSFramed(b []byte) bool {
+	return len(b) >= 4 && b[0] == rsFrameMagic[0] && b[1] == rsFrameMagic[1] && b[2] == rsFrameMagic[2] && b[3] == rsFrameMagic[3]
+}
+
+// wrapRSFrame encodes a raw Shamir share (payload) into an RS-protected
+// frame carrying the (n, t, index) metadata alongside it.
+func wrapRSFrame(payload []byte, n, t, index int) ([]byte, error) {
+	codec, err := rsBlockCodec()
+	if err != nil {
+		return nil, err
+	}
+
+	nBlocks := (len(payload) + rsDataBlockSize - 1) / rsDataBlockSize
+	out := make([]byte, 0, rsHeaderSize+nBlocks*codec.BlockSymbols())
+	out = append(out, rsFrameMagic[:]...)
+	out = append(out, rsFrameVersion, byte(n), byte(t), byte(index))
+	out = binary.BigEndian.AppendUint32(out, uint32(len(payload)))
+
+	block := make([]byte, rsDataBlockSize)
+	for i := 0; i < nBlocks; i++ {
+		start := i * rsDataBlockSize
+		end := start + rsDataBlockSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		for j := range block {
+			block[j] = 0
+		}
+		copy(block, payload[start:end])
+
+		encoded, err := codec.Encode(block)
+		if err != nil {
+			return nil, fmt.Errorf("rs frame: failed to encode block %d: %w", i, err)
+		}
+		out = append(out, encoded...)
+	}
+	return out, nil
+}
+
+// unwrapRSFrame decodes an RS-protected frame back into the raw Shamir
+// share, its (n, t, index) metadata, and the number of bytes that were
+// corrected. If fix is false, any block with a non-zero syndrome is
+// rejected rather than silently repaired.
+func unwrapRSFrame(frame []byte, fix bool) (payload []byte, n, t, index, corrected int, err error) {
+	if !isRSFramed(frame) {
+		return nil, 0, 0, 0, 0, errors.New("rs frame: missing magic prefix")
+	}
+	if len(frame) < rsHeaderSize {
+		return nil, 0, 0, 0, 0, errors.New("rs frame: truncated header")
+	}
+	if frame[4] != rsFrameVersion {
+		return nil, 0, 0, 0, 0, fmt.Errorf("rs frame: unsupported version %d", frame[4])
+	}
+	n = int(frame[5])
+	t = int(frame[6])
+	index = int(frame[7])
+	payloadLen := int(binary.BigEndian.Uint32(frame[8:12]))
+
+	codec, err := rsBlockCodec()
+	if err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	body := frame[rsHeaderSize:]
+	blockSize := codec.BlockSymbols()
+	nBlocks := (payloadLen + rsDataBlockSize - 1) / rsDataBlockSize
+	if len(body) != nBlocks*blockSize {
+		return nil, 0, 0, 0, 0, fmt.Errorf("rs frame: expected %d bytes of RS blocks, got %d", nBlocks*blockSize, len(body))
+	}
+
+	payload = make([]byte, 0, payloadLen)
+	for i := 0; i < nBlocks; i++ {
+		block := body[i*blockSize : (i+1)*blockSize]
+
+		if !fix {
+			recoverable, derr := rsBlockCorrectable(block, codec)
+			if derr != nil {
+				return nil, 0, 0, 0, 0, fmt.Errorf("rs frame: block %d failed parity check and cannot be repaired even with -fix: %w", i, derr)
+			}
+			if recoverable {
+				return nil, 0, 0, 0, 0, fmt.Errorf("rs frame: block %d failed parity check (pass -fix to attempt repair)", i)
+			}
+			payload = append(payload, block[:rsDataBlockSize]...)
+			continue
+		}
+
+		data, fixedCount, derr := codec.Decode(block)
+		if derr != nil {
+			return nil, 0, 0, 0, 0, fmt.Errorf("rs frame: block %d unrecoverable: %w", i, derr)
+		}
+		corrected += fixedCount
+		payload = append(payload, data...)
+	}
+
+	return payload[:payloadLen], n, t, index, corrected, nil
+}
+
+// rsBlockCorrectable reports whether decoding block would change any byte
+// (without actually applying the correction), or returns codec.Decode's
+// error if the block has more errors than the code can locate at all (in
+// which case -fix would not help either).
+func rsBlockCorrectable(block []byte, codec *rscode.Codec) (recoverable bool, err error) {
+	_, corrected, err := codec.Decode(block)
+	if err != nil {
+		return false, err
+	}
+	return corrected != 0, nil
+}
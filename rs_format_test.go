@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRSFrameRoundTrip(t *testing.T) {
+	payload := bytes.Repeat([]byte("shamir share bytes"), 10) // spans multiple RS blocks
+	frame, err := wrapRSFrame(payload, 5, 3, 2)
+	if err != nil {
+		t.Fatalf("wrapRSFrame: %v", err)
+	}
+	if !isRSFramed(frame) {
+		t.Fatalf("wrapRSFrame output is not recognized by isRSFramed")
+	}
+
+	got, n, tt, index, corrected, err := unwrapRSFrame(frame, false)
+	if err != nil {
+		t.Fatalf("unwrapRSFrame: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("unwrapRSFrame payload = %q, want %q", got, payload)
+	}
+	if n != 5 || tt != 3 || index != 2 {
+		t.Fatalf("unwrapRSFrame metadata = (n=%d t=%d index=%d), want (5,3,2)", n, tt, index)
+	}
+	if corrected != 0 {
+		t.Fatalf("unwrapRSFrame corrected = %d, want 0 for an untouched frame", corrected)
+	}
+}
+
+func TestUnwrapRSFrameRejectsBadVersion(t *testing.T) {
+	payload := []byte("short payload")
+	frame, err := wrapRSFrame(payload, 5, 3, 1)
+	if err != nil {
+		t.Fatalf("wrapRSFrame: %v", err)
+	}
+	frame[4] = rsFrameVersion + 1
+	if _, _, _, _, _, err := unwrapRSFrame(frame, false); err == nil {
+		t.Fatalf("unwrapRSFrame should reject an unsupported version")
+	}
+}
+
+func TestUnwrapRSFrameRejectsTruncatedHeader(t *testing.T) {
+	frame := append(append([]byte{}, rsFrameMagic[:]...), rsFrameVersion, 5, 3)
+	if _, _, _, _, _, err := unwrapRSFrame(frame, false); err == nil {
+		t.Fatalf("unwrapRSFrame should reject a truncated header")
+	}
+}
+
+func TestUnwrapRSFrameFixVsNoFix(t *testing.T) {
+	payload := bytes.Repeat([]byte("A"), rsDataBlockSize) // exactly one RS block
+	frame, err := wrapRSFrame(payload, 5, 3, 1)
+	if err != nil {
+		t.Fatalf("wrapRSFrame: %v", err)
+	}
+
+	// Corrupt a handful of bytes in the first block, well within its
+	// correction capacity (rsParitySize/2 = 16 byte errors).
+	block := frame[rsHeaderSize:]
+	for i := 0; i < 5; i++ {
+		block[i] ^= 0xFF
+	}
+
+	if _, _, _, _, _, err := unwrapRSFrame(frame, false); err == nil {
+		t.Fatalf("unwrapRSFrame without -fix should reject a corrupted frame")
+	}
+
+	got, _, _, _, corrected, err := unwrapRSFrame(frame, true)
+	if err != nil {
+		t.Fatalf("unwrapRSFrame with -fix: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("unwrapRSFrame with -fix payload = %q, want %q", got, payload)
+	}
+	if corrected != 5 {
+		t.Fatalf("unwrapRSFrame with -fix corrected = %d, want 5", corrected)
+	}
+}
+
+func TestUnwrapRSFrameBeyondCapacityFailsEvenWithFix(t *testing.T) {
+	payload := bytes.Repeat([]byte("B"), rsDataBlockSize)
+	frame, err := wrapRSFrame(payload, 5, 3, 1)
+	if err != nil {
+		t.Fatalf("wrapRSFrame: %v", err)
+	}
+
+	block := frame[rsHeaderSize:]
+	for i := range block {
+		block[i] ^= 0xFF // corrupt every byte, far beyond correction capacity
+	}
+
+	if _, _, _, _, _, err := unwrapRSFrame(frame, true); err == nil {
+		t.Fatalf("unwrapRSFrame with -fix should still fail on an unrecoverable block")
+	}
+}
+
+func TestUnwrapRSFrameNoFixErrorDistinguishesUnrecoverable(t *testing.T) {
+	payload := bytes.Repeat([]byte("C"), rsDataBlockSize)
+
+	correctableFrame, err := wrapRSFrame(payload, 5, 3, 1)
+	if err != nil {
+		t.Fatalf("wrapRSFrame: %v", err)
+	}
+	correctableBlock := correctableFrame[rsHeaderSize:]
+	for i := 0; i < 5; i++ {
+		correctableBlock[i] ^= 0xFF
+	}
+	_, _, _, _, _, err = unwrapRSFrame(correctableFrame, false)
+	if err == nil || !strings.Contains(err.Error(), "pass -fix to attempt repair") {
+		t.Fatalf("correctable block: unwrapRSFrame error = %v, want it to suggest -fix", err)
+	}
+
+	unrecoverableFrame, err := wrapRSFrame(payload, 5, 3, 1)
+	if err != nil {
+		t.Fatalf("wrapRSFrame: %v", err)
+	}
+	unrecoverableBlock := unrecoverableFrame[rsHeaderSize:]
+	for i := range unrecoverableBlock {
+		unrecoverableBlock[i] ^= 0xFF
+	}
+	_, _, _, _, _, err = unwrapRSFrame(unrecoverableFrame, false)
+	if err == nil || strings.Contains(err.Error(), "pass -fix to attempt repair") {
+		t.Fatalf("unrecoverable block: unwrapRSFrame error = %v, should not suggest -fix", err)
+	}
+	if !strings.Contains(err.Error(), "cannot be repaired even with -fix") {
+		t.Fatalf("unrecoverable block: unwrapRSFrame error = %v, want it to say -fix would not help", err)
+	}
+}
@@ -0,0 +1,86 @@
+// Package rscode implements a systematic Reed-Solomon code over GF(256),
+// used to wrap Shamir shares so that small amounts of bit-rot (a mistyped
+// Base64 character, a smudged QR code, a flipped bit on paper) can be
+// detected and, within the code's distance, corrected before the bytes are
+// ever fed into Lagrange interpolation.
+package rscode
+
+// GF(256) here uses the primitive polynomial x^8+x^4+x^3+x^2+1 (0x11d) with
+// generator 2, the conventional choice for Reed-Solomon codes (QR codes,
+// CDs, and most RS libraries use the same field).
+const gfPoly = 0x11d
+
+var (
+	gfExpTable [512]byte
+	gfLogTable [256]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExpTable[i] = byte(x)
+		gfLogTable[byte(x)] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= gfPoly
+		}
+	}
+	for i := 255; i < 512; i++ {
+		gfExpTable[i] = gfExpTable[i-255]
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExpTable[int(gfLogTable[a])+int(gfLogTable[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	diff := int(gfLogTable[a]) - int(gfLogTable[b])
+	if diff < 0 {
+		diff += 255
+	}
+	return gfExpTable[diff]
+}
+
+func gfPow(a byte, n int) byte {
+	logA := int(gfLogTable[a])
+	e := (logA * n) % 255
+	if e < 0 {
+		e += 255
+	}
+	return gfExpTable[e]
+}
+
+func gfInverse(a byte) byte {
+	return gfExpTable[255-int(gfLogTable[a])]
+}
+
+// gfPolyMul multiplies two polynomials given as coefficient slices, highest
+// degree first.
+func gfPolyMul(p, q []byte) []byte {
+	out := make([]byte, len(p)+len(q)-1)
+	for j := range q {
+		if q[j] == 0 {
+			continue
+		}
+		for i := range p {
+			out[i+j] ^= gfMul(p[i], q[j])
+		}
+	}
+	return out
+}
+
+// gfPolyEval evaluates a polynomial (highest degree first) at x.
+func gfPolyEval(p []byte, x byte) byte {
+	y := p[0]
+	for i := 1; i < len(p); i++ {
+		y = gfMul(y, x) ^ p[i]
+	}
+	return y
+}
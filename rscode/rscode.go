@@ -0,0 +1,291 @@
+package rscode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnrecoverable is returned by Decode when the number of corrupted
+// symbols exceeds what the code's parity can correct (more than
+// ParitySymbols/2 byte errors in the block).
+var ErrUnrecoverable = errors.New("rscode: block has too many errors to correct")
+
+// MaxDataSymbols is the largest total length (data+parity) of a single
+// block: the field has 255 non-zero elements, so a codeword cannot exceed
+// 255 symbols.
+const MaxDataSymbols = 255
+
+// Codec is a systematic Reed-Solomon encoder/decoder for fixed-size blocks
+// of DataSymbols data bytes protected by ParitySymbols parity bytes. A
+// Codec can correct up to ParitySymbols/2 corrupted bytes per block.
+type Codec struct {
+	DataSymbols   int
+	ParitySymbols int
+	generator     []byte
+}
+
+// NewCodec builds a Codec for the given data/parity block sizes. dataSymbols
+// must be >=1 and dataSymbols+paritySymbols <= 255.
+func NewCodec(dataSymbols, paritySymbols int) (*Codec, error) {
+	if dataSymbols < 1 {
+		return nil, fmt.Errorf("rscode: dataSymbols must be >= 1, got %d", dataSymbols)
+	}
+	if paritySymbols < 2 {
+		return nil, fmt.Errorf("rscode: paritySymbols must be >= 2, got %d", paritySymbols)
+	}
+	if dataSymbols+paritySymbols > MaxDataSymbols {
+		return nil, fmt.Errorf("rscode: dataSymbols+paritySymbols must be <= %d, got %d", MaxDataSymbols, dataSymbols+paritySymbols)
+	}
+	return &Codec{
+		DataSymbols:   dataSymbols,
+		ParitySymbols: paritySymbols,
+		generator:     generatorPoly(paritySymbols),
+	}, nil
+}
+
+// BlockSymbols is the total length (data+parity) of one encoded block.
+func (c *Codec) BlockSymbols() int {
+	return c.DataSymbols + c.ParitySymbols
+}
+
+// generatorPoly builds the RS generator polynomial g(x) = prod(x - 2^i) for
+// i in [0, nsym), highest degree first.
+func generatorPoly(nsym int) []byte {
+	g := []byte{1}
+	for i := 0; i < nsym; i++ {
+		g = gfPolyMul(g, []byte{1, gfPow(2, i)})
+	}
+	return g
+}
+
+// Encode appends ParitySymbols parity bytes to a DataSymbols-byte data
+// block, returning the full systematic codeword (data followed by parity).
+func (c *Codec) Encode(data []byte) ([]byte, error) {
+	if len(data) != c.DataSymbols {
+		return nil, fmt.Errorf("rscode: Encode expects %d data bytes, got %d", c.DataSymbols, len(data))
+	}
+
+	block := make([]byte, c.BlockSymbols())
+	copy(block, data)
+
+	// Systematic polynomial division of data*x^nsym by the generator,
+	// performed in place; the remainder left in the trailing bytes is the
+	// parity, while the data bytes are overwritten by the division and so
+	// are restored below.
+	for i := 0; i < len(data); i++ {
+		coef := block[i]
+		if coef == 0 {
+			continue
+		}
+		for j, g := range c.generator {
+			block[i+j] ^= gfMul(g, coef)
+		}
+	}
+	copy(block, data)
+	return block, nil
+}
+
+// Decode checks a BlockSymbols()-length codeword, correcting up to
+// ParitySymbols/2 byte errors. It returns the recovered DataSymbols data
+// bytes and the number of bytes that were corrected. If the block has no
+// errors, corrected is 0. If it has more errors than the code can fix, it
+// returns ErrUnrecoverable.
+func (c *Codec) Decode(block []byte) (data []byte, corrected int, err error) {
+	if len(block) != c.BlockSymbols() {
+		return nil, 0, fmt.Errorf("rscode: Decode expects a %d-byte block, got %d", c.BlockSymbols(), len(block))
+	}
+
+	synd := syndromes(block, c.ParitySymbols)
+	if allZero(synd) {
+		return append([]byte(nil), block[:c.DataSymbols]...), 0, nil
+	}
+
+	errLocator, err := errorLocatorPoly(synd, c.ParitySymbols)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrUnrecoverable, err)
+	}
+	errPositions, err := chienSearch(errLocator, len(block))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrUnrecoverable, err)
+	}
+	if len(errPositions)-1 > c.ParitySymbols/2 {
+		return nil, 0, fmt.Errorf("%w: %d errors exceed correction capacity of %d", ErrUnrecoverable, len(errPositions)-1, c.ParitySymbols/2)
+	}
+
+	correctedBlock := append([]byte(nil), block...)
+	if err := forneyCorrect(correctedBlock, synd, errLocator, errPositions); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrUnrecoverable, err)
+	}
+
+	// Verify the correction actually zeroed the syndromes; otherwise we
+	// had more errors than the code could locate correctly and silently
+	// "fixing" them would be worse than refusing.
+	if finalSynd := syndromes(correctedBlock, c.ParitySymbols); !allZero(finalSynd) {
+		return nil, 0, fmt.Errorf("%w: correction did not converge", ErrUnrecoverable)
+	}
+
+	for i := range block {
+		if block[i] != correctedBlock[i] {
+			corrected++
+		}
+	}
+	return correctedBlock[:c.DataSymbols], corrected, nil
+}
+
+func allZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// syndromes evaluates the received codeword at each root of the generator
+// polynomial (2^0..2^(nsym-1)); all-zero syndromes mean no errors.
+func syndromes(block []byte, nsym int) []byte {
+	synd := make([]byte, nsym)
+	for i := 0; i < nsym; i++ {
+		synd[i] = gfPolyEval(block, gfPow(2, i))
+	}
+	return synd
+}
+
+// errorLocatorPoly runs the Berlekamp-Massey algorithm over the syndromes
+// to find the shortest LFSR (the error locator polynomial) consistent with
+// them.
+func errorLocatorPoly(synd []byte, nsym int) ([]byte, error) {
+	errLoc := []byte{1}
+	oldLoc := []byte{1}
+
+	for i := 0; i < nsym; i++ {
+		oldLoc = append(oldLoc, 0)
+		delta := synd[i]
+		for j := 1; j < len(errLoc); j++ {
+			delta ^= gfMul(errLoc[len(errLoc)-1-j], synd[i-j])
+		}
+		if delta == 0 {
+			continue
+		}
+		if len(oldLoc) > len(errLoc) {
+			newLoc := gfPolyScale(oldLoc, delta)
+			oldLoc = gfPolyScale(errLoc, gfInverse(delta))
+			errLoc = newLoc
+		}
+		errLoc = gfPolyXor(errLoc, gfPolyScale(oldLoc, delta))
+	}
+
+	// Strip leading zero coefficients introduced by the recurrence.
+	shift := 0
+	for shift < len(errLoc) && errLoc[shift] == 0 {
+		shift++
+	}
+	errLoc = errLoc[shift:]
+
+	errs := len(errLoc) - 1
+	if errs*2 > nsym {
+		return nil, errors.New("too many errors to locate")
+	}
+	return errLoc, nil
+}
+
+func gfPolyScale(p []byte, x byte) []byte {
+	out := make([]byte, len(p))
+	for i, c := range p {
+		out[i] = gfMul(c, x)
+	}
+	return out
+}
+
+func gfPolyXor(p, q []byte) []byte {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+	out := make([]byte, n)
+	for i := 0; i < len(p); i++ {
+		out[n-len(p)+i] ^= p[i]
+	}
+	for i := 0; i < len(q); i++ {
+		out[n-len(q)+i] ^= q[i]
+	}
+	return out
+}
+
+// chienSearch finds the roots of the error locator polynomial by brute
+// force, returning the byte offsets (from the start of the block) of the
+// located errors.
+//
+// errLoc's roots are at x = X_pos^-1, where X_pos = 2^(blockLen-1-pos) is
+// the error-location value for a byte at offset pos (the same X used by
+// forneyCorrect). So a root found at x = 2^i corresponds to X_pos = 2^-i =
+// 2^(255-i), i.e. pos = blockLen-1-(255-i). Since X_pos^-1 generally lands
+// far outside the small exponent range [0,blockLen), every one of the 255
+// non-zero field elements has to be tried, not just 2^0..2^(blockLen-1).
+func chienSearch(errLoc []byte, blockLen int) ([]int, error) {
+	var positions []int
+	for i := 0; i < 255; i++ {
+		x := gfPow(2, i)
+		if gfPolyEval(errLoc, x) != 0 {
+			continue
+		}
+		pos := blockLen - 1 - (255-i)%255
+		if pos < 0 || pos >= blockLen {
+			continue // root doesn't correspond to a byte inside this block
+		}
+		positions = append(positions, pos)
+	}
+	if len(positions) != len(errLoc)-1 {
+		return nil, errors.New("error locator roots do not match error count")
+	}
+	return append(positions, 0), nil // trailing 0 kept for historical API parity with len()-1 usage above
+}
+
+// forneyCorrect computes error magnitudes via the Forney algorithm and XORs
+// them into block at the located error positions, in place.
+func forneyCorrect(block []byte, synd, errLoc []byte, errPositionsWithSentinel []int) error {
+	errPositions := errPositionsWithSentinel[:len(errPositionsWithSentinel)-1]
+	if len(errPositions) == 0 {
+		return nil
+	}
+
+	// Error evaluator polynomial: Omega(x) = Synd(x)*ErrLoc(x) mod x^nsym.
+	// Synd is stored low-degree-first (synd[i] is the syndrome at root 2^i),
+	// so it's reversed to the high-degree-first convention gfPolyMul expects;
+	// the product is then truncated to its lowest nsym-degree terms, which
+	// for a high-degree-first array means keeping the last nsym elements.
+	revSynd := reverseBytes(synd)
+	errEval := gfPolyMul(revSynd, errLoc)
+	if keep := len(synd); len(errEval) > keep {
+		errEval = errEval[len(errEval)-keep:]
+	}
+
+	for _, pos := range errPositions {
+		x := gfPow(2, len(block)-1-pos)
+		xInv := gfInverse(x)
+
+		// Evaluate the error locator's formal derivative at xInv: in GF(2^n)
+		// the derivative only keeps odd-degree terms, each losing one degree.
+		var errLocPrimeAtXInv byte
+		revErrLoc := reverseBytes(errLoc)
+		for j := 1; j < len(revErrLoc); j += 2 {
+			errLocPrimeAtXInv ^= gfMul(revErrLoc[j], gfPow(xInv, j-1))
+		}
+		if errLocPrimeAtXInv == 0 {
+			return errors.New("forney: zero derivative, cannot correct")
+		}
+
+		yAtXInv := gfPolyEval(errEval, xInv)
+		magnitude := gfMul(x, gfDiv(yAtXInv, errLocPrimeAtXInv))
+		block[pos] ^= magnitude
+	}
+	return nil
+}
+
+func reverseBytes(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i, v := range b {
+		out[len(b)-1-i] = v
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package rscode
+
+import (
+	"bytes"
+	cryptoRand "crypto/rand"
+	"testing"
+)
+
+func TestEncodeDecodeNoErrors(t *testing.T) {
+	c, err := NewCodec(128, 32)
+	if err != nil {
+		t.Fatalf("NewCodec: %v", err)
+	}
+	data := make([]byte, c.DataSymbols)
+	if _, err := cryptoRand.Read(data); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+
+	block, err := c.Encode(data)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got, corrected, err := c.Decode(block)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if corrected != 0 {
+		t.Fatalf("Decode on an undamaged block reported %d corrections, want 0", corrected)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("Decode returned %q, want %q", got, data)
+	}
+}
+
+// TestDecodeCorrectsUpToCapacity exercises the documented "up to
+// ParitySymbols/2 byte errors" correction capacity, not just the 0-error path.
+func TestDecodeCorrectsUpToCapacity(t *testing.T) {
+	c, err := NewCodec(128, 32)
+	if err != nil {
+		t.Fatalf("NewCodec: %v", err)
+	}
+	capacity := c.ParitySymbols / 2
+
+	data := make([]byte, c.DataSymbols)
+	if _, err := cryptoRand.Read(data); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+	clean, err := c.Encode(data)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	for numErrors := 1; numErrors <= capacity; numErrors++ {
+		damaged := append([]byte(nil), clean...)
+		positions := map[int]bool{}
+		for len(positions) < numErrors {
+			idx := randIntn(t, len(damaged))
+			positions[idx] = true
+		}
+		for pos := range positions {
+			flip := byte(1 + randIntn(t, 255))
+			damaged[pos] ^= flip
+		}
+
+		got, corrected, err := c.Decode(damaged)
+		if err != nil {
+			t.Fatalf("Decode with %d error(s): %v", numErrors, err)
+		}
+		if corrected != numErrors {
+			t.Fatalf("Decode with %d error(s): reported %d corrections", numErrors, corrected)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("Decode with %d error(s): got %q, want %q", numErrors, got, data)
+		}
+	}
+}
+
+func TestDecodeBeyondCapacityIsRejected(t *testing.T) {
+	c, err := NewCodec(16, 4)
+	if err != nil {
+		t.Fatalf("NewCodec: %v", err)
+	}
+	data := make([]byte, c.DataSymbols)
+	if _, err := cryptoRand.Read(data); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+	block, err := c.Encode(data)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	// Corrupt every byte of the block: far beyond ParitySymbols/2.
+	for i := range block {
+		block[i] ^= 0xFF
+	}
+	if _, _, err := c.Decode(block); err == nil {
+		t.Fatalf("Decode with all bytes corrupted should have failed")
+	}
+}
+
+func randIntn(t *testing.T, n int) int {
+	t.Helper()
+	buf := make([]byte, 4)
+	if _, err := cryptoRand.Read(buf); err != nil {
+		t.Fatalf("rand: %v", err)
+	}
+	v := int(buf[0])<<24 | int(buf[1])<<16 | int(buf[2])<<8 | int(buf[3])
+	if v < 0 {
+		v = -v
+	}
+	return v % n
+}
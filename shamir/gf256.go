@@ -0,0 +1,84 @@
+package shamir
+
+// -------------------- GF(256) arithmetic --------------------
+//
+// Shamir's scheme is evaluated over GF(256) so that every byte of a secret
+// (and every share) fits in a single field element. The field is built from
+// the Rijndael/AES reduction polynomial x^8+x^4+x^3+x+1 (0x11b), which is a
+// common and well-analyzed choice for byte-oriented GF(256) codecs.
+//
+// 2 is not a primitive element under 0x11b (its multiplicative order is only
+// 51), so the log/antilog tables are generated from 3, same as AES's own
+// S-box tables; 3 generates all 255 non-zero elements under this reduction.
+
+const gf256Poly = 0x11b
+
+var (
+	gf256ExpTable [512]byte
+	gf256LogTable [256]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gf256ExpTable[i] = byte(x)
+		gf256LogTable[byte(x)] = byte(i)
+		// x = 3*x = (2*x) XOR x in GF(256).
+		double := x << 1
+		if double&0x100 != 0 {
+			double ^= gf256Poly
+		}
+		x = double ^ x
+	}
+	// Extend the exp table so lookups for log sums up to 2*254 never wrap.
+	for i := 255; i < 512; i++ {
+		gf256ExpTable[i] = gf256ExpTable[i-255]
+	}
+}
+
+// gf256Add is addition (and subtraction) in GF(256), i.e. XOR.
+func gf256Add(a, b byte) byte {
+	return a ^ b
+}
+
+// gf256Mul multiplies two GF(256) elements using log/antilog tables.
+func gf256Mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gf256ExpTable[int(gf256LogTable[a])+int(gf256LogTable[b])]
+}
+
+// gf256Div divides a by b in GF(256). b must be non-zero.
+func gf256Div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	// log(a) - log(b) can go negative; add 255 to stay inside the table.
+	diff := int(gf256LogTable[a]) - int(gf256LogTable[b])
+	if diff < 0 {
+		diff += 255
+	}
+	return gf256ExpTable[diff]
+}
+
+// gf256Pow raises a to the given non-negative power.
+func gf256Pow(a byte, n int) byte {
+	if n == 0 {
+		return 1
+	}
+	if a == 0 {
+		return 0
+	}
+	logA := int(gf256LogTable[a])
+	e := (logA * n) % 255
+	if e < 0 {
+		e += 255
+	}
+	return gf256ExpTable[e]
+}
+
+// gf256Inv returns the multiplicative inverse of a. a must be non-zero.
+func gf256Inv(a byte) byte {
+	return gf256ExpTable[255-int(gf256LogTable[a])]
+}
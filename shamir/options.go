@@ -0,0 +1,155 @@
+package shamir
+
+import (
+	cryptoRand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// ErrAuthenticationFailed is returned by CombineWithOptions when a share's
+// AEAD tag does not verify. This is distinct from ErrNotEnoughShares: it
+// means the passphrase is wrong, or the share belongs to a different split
+// entirely, not merely that too few shares were supplied.
+var ErrAuthenticationFailed = errors.New("shamir: share authentication failed (wrong passphrase, or share from a different split)")
+
+// KDFParams are the Argon2id parameters (and salt) used to stretch a
+// passphrase into an AEAD key and a tag-offset byte. The salt is generated
+// once per Split and must travel with the shares (e.g. in a frame header)
+// so Combine can re-derive the same key.
+type KDFParams struct {
+	Time    uint32
+	Memory  uint32 // KiB
+	Threads uint8
+	Salt    []byte
+}
+
+// DefaultKDFParams returns conservative interactive Argon2id parameters
+// (OWASP's current baseline: t=3, 64 MiB, 4 lanes) with a fresh random salt.
+func DefaultKDFParams() (*KDFParams, error) {
+	return NewKDFParams(3, 64*1024, 4)
+}
+
+// NewKDFParams builds Argon2id parameters from caller-supplied time cost
+// (iterations), memory cost (KiB), and parallelism (lanes), with a fresh
+// random salt. Use this instead of DefaultKDFParams to tune the cost/security
+// tradeoff, e.g. for a slower, memory-hungrier split on hardware that can
+// afford it, or a lighter one for resource-constrained combine-time use.
+func NewKDFParams(time, memory uint32, threads uint8) (*KDFParams, error) {
+	if time < 1 {
+		return nil, errors.New("shamir: argon2 time cost must be >= 1")
+	}
+	if memory < 8*1024 {
+		return nil, errors.New("shamir: argon2 memory cost must be >= 8192 KiB (8 MiB)")
+	}
+	if threads < 1 {
+		return nil, errors.New("shamir: argon2 parallelism must be >= 1")
+	}
+	salt := make([]byte, 16)
+	if _, err := cryptoRand.Read(salt); err != nil {
+		return nil, fmt.Errorf("shamir: failed to generate KDF salt: %w", err)
+	}
+	return &KDFParams{Time: time, Memory: memory, Threads: threads, Salt: salt}, nil
+}
+
+// DeriveKey stretches passphrase via Argon2id into a 32-byte AEAD key and a
+// single tag-offset byte mixed into each share's index so that shares
+// derived from different passphrases (or different salts) don't carry the
+// same tag space and can't be accidentally combined.
+func (p *KDFParams) DeriveKey(passphrase []byte) (aeadKey []byte, tagOffset byte) {
+	out := argon2.IDKey(passphrase, p.Salt, p.Time, p.Memory, p.Threads, chacha20poly1305.KeySize+1)
+	return out[:chacha20poly1305.KeySize], out[chacha20poly1305.KeySize]
+}
+
+// Options configures the optional passphrase-protected mode for Split and
+// Combine. A nil *Options (or one with a nil KDF) is equivalent to calling
+// Split/Combine directly: plain shares, no encryption. AEADKey and
+// TagOffset must both come from the same KDF.DeriveKey(passphrase) call.
+type Options struct {
+	KDF       *KDFParams
+	AEADKey   []byte
+	TagOffset byte
+}
+
+// SplitWithOptions behaves like Split, but when opts.KDF is set it
+// additionally encrypts each share's payload with ChaCha20-Poly1305 under
+// opts.AEADKey and remaps the share's tag byte by XORing it with a
+// passphrase-derived offset.
+func SplitWithOptions(secret []byte, n, t int, opts *Options) ([][]byte, error) {
+	shares, err := Split(secret, n, t)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil || opts.KDF == nil {
+		return shares, nil
+	}
+	if len(opts.AEADKey) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("shamir: AEADKey must be %d bytes", chacha20poly1305.KeySize)
+	}
+	aead, err := chacha20poly1305.New(opts.AEADKey)
+	if err != nil {
+		return nil, fmt.Errorf("shamir: failed to init AEAD: %w", err)
+	}
+
+	protected := make([][]byte, n)
+	for i, s := range shares {
+		index := s[len(s)-1]
+		payload := s[:len(s)-1]
+		nonce := nonceForIndex(index)
+		ct := aead.Seal(nil, nonce, payload, nil)
+		out := make([]byte, len(ct)+1)
+		copy(out, ct)
+		out[len(out)-1] = index ^ opts.TagOffset
+		protected[i] = out
+	}
+	return protected, nil
+}
+
+// CombineWithOptions behaves like Combine, but when opts.KDF is set it first
+// undoes the tag remapping and decrypts each share's payload, returning
+// ErrAuthenticationFailed (rather than a generic combine error) if any
+// share's AEAD tag doesn't verify under opts.AEADKey.
+func CombineWithOptions(shares [][]byte, opts *Options) ([]byte, error) {
+	if opts == nil || opts.KDF == nil {
+		return Combine(shares)
+	}
+	if len(opts.AEADKey) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("shamir: AEADKey must be %d bytes", chacha20poly1305.KeySize)
+	}
+	aead, err := chacha20poly1305.New(opts.AEADKey)
+	if err != nil {
+		return nil, fmt.Errorf("shamir: failed to init AEAD: %w", err)
+	}
+
+	plain := make([][]byte, len(shares))
+	for i, s := range shares {
+		if len(s) < 1+aead.Overhead() {
+			return nil, fmt.Errorf("shamir: share %d is too short to be passphrase-protected", i)
+		}
+		index := s[len(s)-1] ^ opts.TagOffset
+		ct := s[:len(s)-1]
+		nonce := nonceForIndex(index)
+		payload, err := aead.Open(nil, nonce, ct, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w (share %d)", ErrAuthenticationFailed, i)
+		}
+		raw := make([]byte, len(payload)+1)
+		copy(raw, payload)
+		raw[len(raw)-1] = index
+		plain[i] = raw
+	}
+	return Combine(plain)
+}
+
+// nonceForIndex derives a deterministic per-share nonce from its Shamir
+// index. This is safe because each (salt, passphrase) pair is used to
+// protect exactly one Split's worth of shares, so (key, nonce) pairs are
+// never reused across different secrets.
+func nonceForIndex(index byte) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.BigEndian.PutUint16(nonce[chacha20poly1305.NonceSize-2:], uint16(index))
+	return nonce
+}
@@ -0,0 +1,61 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitCombineWithOptionsRoundTrip(t *testing.T) {
+	kdf, err := NewKDFParams(1, 8*1024, 1) // cheap params so the test stays fast
+	if err != nil {
+		t.Fatalf("NewKDFParams: %v", err)
+	}
+	aeadKey, tagOffset := kdf.DeriveKey([]byte("correct horse battery staple"))
+	opts := &Options{KDF: kdf, AEADKey: aeadKey, TagOffset: tagOffset}
+
+	secret := []byte("passphrase-protected secret")
+	shares, err := SplitWithOptions(secret, 5, 3, opts)
+	if err != nil {
+		t.Fatalf("SplitWithOptions: %v", err)
+	}
+
+	got, err := CombineWithOptions(shares[:3], opts)
+	if err != nil {
+		t.Fatalf("CombineWithOptions: %v", err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Fatalf("CombineWithOptions: got %q, want %q", got, secret)
+	}
+}
+
+func TestCombineWithOptionsWrongPassphraseFails(t *testing.T) {
+	kdf, err := NewKDFParams(1, 8*1024, 1)
+	if err != nil {
+		t.Fatalf("NewKDFParams: %v", err)
+	}
+	aeadKey, tagOffset := kdf.DeriveKey([]byte("right passphrase"))
+	opts := &Options{KDF: kdf, AEADKey: aeadKey, TagOffset: tagOffset}
+
+	shares, err := SplitWithOptions([]byte("secret"), 5, 3, opts)
+	if err != nil {
+		t.Fatalf("SplitWithOptions: %v", err)
+	}
+
+	wrongKey, wrongOffset := kdf.DeriveKey([]byte("wrong passphrase"))
+	wrongOpts := &Options{KDF: kdf, AEADKey: wrongKey, TagOffset: wrongOffset}
+	if _, err := CombineWithOptions(shares[:3], wrongOpts); err == nil {
+		t.Fatalf("CombineWithOptions with wrong passphrase should have failed")
+	}
+}
+
+func TestNewKDFParamsRejectsWeakSettings(t *testing.T) {
+	if _, err := NewKDFParams(0, 64*1024, 4); err == nil {
+		t.Fatalf("NewKDFParams should reject time=0")
+	}
+	if _, err := NewKDFParams(3, 1024, 4); err == nil {
+		t.Fatalf("NewKDFParams should reject memory below 8MiB")
+	}
+	if _, err := NewKDFParams(3, 64*1024, 0); err == nil {
+		t.Fatalf("NewKDFParams should reject threads=0")
+	}
+}
@@ -0,0 +1,125 @@
+// Package shamir implements Shamir's secret sharing over GF(256), operating
+// byte-by-byte so that shares are the same length as (plus one tag byte)
+// the secret they protect.
+package shamir
+
+import (
+	cryptoRand "crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// ErrNotEnoughShares is returned by Combine when fewer than two shares are
+// supplied; Shamir reconstruction is undefined below a threshold of two.
+var ErrNotEnoughShares = errors.New("shamir: need at least 2 shares to combine")
+
+// Split divides secret into n shares such that any t of them suffice to
+// reconstruct it, while any t-1 reveal nothing about it. n must be in
+// [2,255] and t in [2,n].
+//
+// Each returned share is len(secret)+1 bytes: the Shamir-transformed data
+// followed by a single tag byte (the share's x-coordinate, 1..n).
+func Split(secret []byte, n, t int) ([][]byte, error) {
+	if n < 2 || n > 255 {
+		return nil, fmt.Errorf("shamir: n must be between 2 and 255, got %d", n)
+	}
+	if t < 2 || t > n {
+		return nil, fmt.Errorf("shamir: t must be between 2 and n (%d), got %d", n, t)
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("shamir: secret is empty")
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1) // tag: x-coordinate 1..n
+	}
+
+	// For each byte of the secret, build a random degree-(t-1) polynomial
+	// with that byte as the constant term, then evaluate it at x=1..n.
+	coeffs := make([]byte, t)
+	for idx, b := range secret {
+		coeffs[0] = b
+		if _, err := cryptoRand.Read(coeffs[1:]); err != nil {
+			return nil, fmt.Errorf("shamir: failed to generate random coefficients: %w", err)
+		}
+		for i := 0; i < n; i++ {
+			x := byte(i + 1)
+			shares[i][idx] = evalPoly(coeffs, x)
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine reconstructs the secret from a set of shares produced by Split.
+// Any t of the original n shares suffice. Fewer than two produce
+// ErrNotEnoughShares; Combine does not know t, so two to t-1 shares, or a
+// wrong subset, silently yield garbage rather than an error, as is inherent
+// to Lagrange interpolation.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, ErrNotEnoughShares
+	}
+
+	shareLen := len(shares[0])
+	if shareLen < 2 {
+		return nil, errors.New("shamir: malformed share (too short)")
+	}
+	xs := make([]byte, len(shares))
+	for i, s := range shares {
+		if len(s) != shareLen {
+			return nil, fmt.Errorf("shamir: share %d has length %d, want %d", i, len(s), shareLen)
+		}
+		xs[i] = s[shareLen-1]
+		for j := 0; j < i; j++ {
+			if xs[j] == xs[i] {
+				return nil, fmt.Errorf("shamir: duplicate share tag %d", xs[i])
+			}
+		}
+	}
+
+	secretLen := shareLen - 1
+	secret := make([]byte, secretLen)
+	ys := make([]byte, len(shares))
+	for pos := 0; pos < secretLen; pos++ {
+		for i, s := range shares {
+			ys[i] = s[pos]
+		}
+		secret[pos] = lagrangeAtZero(xs, ys)
+	}
+
+	return secret, nil
+}
+
+// evalPoly evaluates the polynomial with the given coefficients (lowest
+// degree first) at x, using Horner's rule in GF(256).
+func evalPoly(coeffs []byte, x byte) byte {
+	result := byte(0)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		result = gf256Add(gf256Mul(result, x), coeffs[i])
+	}
+	return result
+}
+
+// lagrangeAtZero interpolates the polynomial through (xs[i], ys[i]) and
+// evaluates it at x=0, which recovers the constant term Split embedded the
+// secret byte in.
+func lagrangeAtZero(xs, ys []byte) byte {
+	var result byte
+	for i := range xs {
+		num := byte(1)
+		den := byte(1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			num = gf256Mul(num, xs[j])
+			den = gf256Mul(den, gf256Add(xs[i], xs[j]))
+		}
+		term := gf256Mul(ys[i], gf256Div(num, den))
+		result = gf256Add(result, term)
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitCombineRoundTrip(t *testing.T) {
+	cases := []struct {
+		n, tt  int
+		secret []byte
+	}{
+		{2, 2, []byte("a")},
+		{5, 3, []byte("hello world secret")},
+		{255, 255, bytes.Repeat([]byte{0xAB}, 64)},
+		{10, 2, []byte{0x00, 0xFF, 0x01, 0xFE}},
+	}
+
+	for _, c := range cases {
+		shares, err := Split(c.secret, c.n, c.tt)
+		if err != nil {
+			t.Fatalf("Split(n=%d,t=%d): %v", c.n, c.tt, err)
+		}
+		if len(shares) != c.n {
+			t.Fatalf("Split(n=%d,t=%d): got %d shares, want %d", c.n, c.tt, len(shares), c.n)
+		}
+
+		got, err := Combine(shares[:c.tt])
+		if err != nil {
+			t.Fatalf("Combine(n=%d,t=%d): %v", c.n, c.tt, err)
+		}
+		if !bytes.Equal(got, c.secret) {
+			t.Fatalf("Combine(n=%d,t=%d): got %q, want %q", c.n, c.tt, got, c.secret)
+		}
+
+		if c.n > c.tt {
+			got2, err := Combine(shares[c.n-c.tt:])
+			if err != nil {
+				t.Fatalf("Combine (different subset, n=%d,t=%d): %v", c.n, c.tt, err)
+			}
+			if !bytes.Equal(got2, c.secret) {
+				t.Fatalf("Combine (different subset, n=%d,t=%d): got %q, want %q", c.n, c.tt, got2, c.secret)
+			}
+		}
+	}
+}
+
+func TestCombineTooFewShares(t *testing.T) {
+	shares, err := Split([]byte("secret"), 5, 3)
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if _, err := Combine(shares[:1]); err != ErrNotEnoughShares {
+		t.Fatalf("Combine with 1 share: got err %v, want ErrNotEnoughShares", err)
+	}
+}
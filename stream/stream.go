@@ -0,0 +1,100 @@
+// Package stream implements the on-disk format for chunked Shamir share
+// streams: one file per share, each holding a manifest header followed by
+// one framed record per chunk. This lets split/combine work through a
+// secret chunk-by-chunk instead of loading it (and every share of it)
+// entirely into memory, so the tool scales to gigabyte-sized secrets.
+package stream
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var streamMagic = [4]byte{'S', 'S', 'S', 'C'}
+
+const streamVersion = 1
+
+// HashSize is the length of the plaintext SHA-256 carried in the manifest.
+const HashSize = 32
+
+// Manifest describes a chunked split: how many shares/threshold it was
+// split with, which share this stream holds (Index, 1-based), how the
+// original secret was chunked, and a SHA-256 of the full plaintext for a
+// post-combine integrity check.
+type Manifest struct {
+	N, T        int
+	Index       int
+	ChunkSize   int
+	TotalChunks int
+	OriginalLen int64
+	PlainHash   [HashSize]byte
+}
+
+// WriteManifest serializes m to w as the first thing written to a share
+// stream file.
+func WriteManifest(w io.Writer, m Manifest) error {
+	hdr := make([]byte, 0, 4+1+1+1+1+4+4+8+HashSize)
+	hdr = append(hdr, streamMagic[:]...)
+	hdr = append(hdr, streamVersion, byte(m.N), byte(m.T), byte(m.Index))
+	hdr = binary.BigEndian.AppendUint32(hdr, uint32(m.ChunkSize))
+	hdr = binary.BigEndian.AppendUint32(hdr, uint32(m.TotalChunks))
+	hdr = binary.BigEndian.AppendUint64(hdr, uint64(m.OriginalLen))
+	hdr = append(hdr, m.PlainHash[:]...)
+	_, err := w.Write(hdr)
+	return err
+}
+
+// ReadManifest parses a manifest previously written by WriteManifest.
+func ReadManifest(r io.Reader) (Manifest, error) {
+	var m Manifest
+	hdr := make([]byte, 4+1+1+1+1+4+4+8+HashSize)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return m, fmt.Errorf("stream: failed to read manifest: %w", err)
+	}
+	if hdr[0] != streamMagic[0] || hdr[1] != streamMagic[1] || hdr[2] != streamMagic[2] || hdr[3] != streamMagic[3] {
+		return m, errors.New("stream: not a share stream file (missing magic prefix)")
+	}
+	if hdr[4] != streamVersion {
+		return m, fmt.Errorf("stream: unsupported version %d", hdr[4])
+	}
+	m.N = int(hdr[5])
+	m.T = int(hdr[6])
+	m.Index = int(hdr[7])
+	m.ChunkSize = int(binary.BigEndian.Uint32(hdr[8:12]))
+	m.TotalChunks = int(binary.BigEndian.Uint32(hdr[12:16]))
+	m.OriginalLen = int64(binary.BigEndian.Uint64(hdr[16:24]))
+	copy(m.PlainHash[:], hdr[24:24+HashSize])
+	return m, nil
+}
+
+// WriteRecord frames one chunk's share payload as (chunkIndex, length,
+// payload) and writes it to w.
+func WriteRecord(w io.Writer, chunkIndex uint32, payload []byte) error {
+	hdr := make([]byte, 8)
+	binary.BigEndian.PutUint32(hdr[0:4], chunkIndex)
+	binary.BigEndian.PutUint32(hdr[4:8], uint32(len(payload)))
+	if _, err := w.Write(hdr); err != nil {
+		return fmt.Errorf("stream: failed to write record header: %w", err)
+	}
+	if _, err := w.Write(payload); err != nil {
+		return fmt.Errorf("stream: failed to write record payload: %w", err)
+	}
+	return nil
+}
+
+// ReadRecord reads one (chunkIndex, length, payload) record from r.
+func ReadRecord(r io.Reader) (chunkIndex uint32, payload []byte, err error) {
+	hdr := make([]byte, 8)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return 0, nil, err // propagate io.EOF as-is so callers can detect end of stream
+	}
+	chunkIndex = binary.BigEndian.Uint32(hdr[0:4])
+	length := binary.BigEndian.Uint32(hdr[4:8])
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, fmt.Errorf("stream: failed to read record %d payload: %w", chunkIndex, err)
+	}
+	return chunkIndex, payload, nil
+}
@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestManifestRoundTrip(t *testing.T) {
+	want := Manifest{
+		N:           5,
+		T:           3,
+		Index:       2,
+		ChunkSize:   4096,
+		TotalChunks: 10,
+		OriginalLen: 40960,
+		PlainHash:   [HashSize]byte{1, 2, 3},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteManifest(&buf, want); err != nil {
+		t.Fatalf("WriteManifest: %v", err)
+	}
+	got, err := ReadManifest(&buf)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if got != want {
+		t.Fatalf("ReadManifest = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadManifestRejectsBadMagic(t *testing.T) {
+	// Padded past the full manifest length so the read succeeds and the
+	// rejection comes from the magic check, not from a short read.
+	buf := bytes.NewBuffer(append([]byte("not a share stream at all...."), make([]byte, 64)...))
+	if _, err := ReadManifest(buf); err == nil {
+		t.Fatalf("ReadManifest should reject a buffer without the magic prefix")
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	payload := []byte("chunk payload bytes")
+	if err := WriteRecord(&buf, 7, payload); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+
+	idx, got, err := ReadRecord(&buf)
+	if err != nil {
+		t.Fatalf("ReadRecord: %v", err)
+	}
+	if idx != 7 || !bytes.Equal(got, payload) {
+		t.Fatalf("ReadRecord = (%d, %q), want (7, %q)", idx, got, payload)
+	}
+
+	if _, _, err := ReadRecord(&buf); err != io.EOF {
+		t.Fatalf("ReadRecord at end of stream = %v, want io.EOF", err)
+	}
+}